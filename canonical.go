@@ -0,0 +1,284 @@
+package qfl
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Canonical returns a copy of the filter in a canonical form, so filters with
+// the same rules are equal regardless of the order they were added. Keys are
+// sorted by name, the rules of a key are sorted, and the values of `eq` and
+// `ne` rules are sorted without duplicates.
+//
+// Groups of the expression have their children sorted, with the nested groups
+// of the same operator merged into them. The sort fields keep their order, as
+// it changes the results.
+func (f *Filter) Canonical() *Filter {
+	root := f.canonicalNode(f.expression())
+
+	// Collect the distinct rules of each key, sorted by their identifier
+	rules := map[string][]canonicalRule{}
+	root.walk(func(rule canonicalRule) {
+		if !slices.ContainsFunc(rules[rule.key], func(r canonicalRule) bool { return r.id == rule.id }) {
+			rules[rule.key] = append(rules[rule.key], rule)
+		}
+	})
+
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	canonical := &Filter{sort: slices.Clone(f.sort), fields: slices.Clone(f.fields)}
+	if f.cursor != nil {
+		canonical.cursor = &Cursor{Values: slices.Clone(f.cursor.Values), Backward: f.cursor.Backward}
+	}
+	if f.page != nil {
+		page := *f.page
+		canonical.page = &page
+	}
+
+	leaves := map[string]ruleNode{}
+	for i, key := range keys {
+		slices.SortFunc(rules[key], func(a, b canonicalRule) int { return strings.Compare(a.id, b.id) })
+		for j, rule := range rules[key] {
+			canonical.addCanonicalRule(rule)
+			leaves[rule.id] = ruleNode{key: i, rule: j}
+		}
+	}
+
+	if root.hasOr() {
+		expr := root.ruleNode(leaves)
+		canonical.expr = &expr
+	}
+
+	return canonical
+}
+
+// Fingerprint returns a digest of the canonical form of the filter, which is
+// the same for filters with the same rules, sort, cursor, page and fields. It
+// can be used as the cache key of the results of the filter.
+func (f *Filter) Fingerprint() string {
+	root := f.canonicalNode(f.expression())
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "rules=%s\n", root.id)
+	fmt.Fprintf(hash, "sort=%s\n", sortString(f.sort))
+	if f.cursor != nil {
+		fmt.Fprintf(hash, "cursor=%t", f.cursor.Backward)
+		for _, value := range f.cursor.Values {
+			fmt.Fprintf(hash, ",%T:%s", value, canonicalValue(value))
+		}
+		fmt.Fprintln(hash)
+	}
+	if f.page != nil {
+		fmt.Fprintf(hash, "page=%d,%d\n", f.page.Limit, f.page.Number)
+	}
+	if f.fields != nil {
+		fmt.Fprintf(hash, "fields=%q\n", f.fields)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// canonicalRule is a rule with the values copied out of the filter, and an
+// identifier that is equal for rules with the same content.
+type canonicalRule struct {
+	key         string
+	Type        ruleType
+	comparasion ComparasionType
+	values      []any
+	id          string
+}
+
+// canonicalNode is a node of the expression with its children sorted by their
+// identifier, which is equal for nodes with the same content.
+type canonicalNode struct {
+	op       logicalOp
+	rule     canonicalRule
+	children []canonicalNode
+	id       string
+}
+
+func (f *Filter) canonicalNode(node ruleNode) canonicalNode {
+	if node.op == logicalLeaf {
+		rule := f.canonicalRule(node)
+		return canonicalNode{rule: rule, id: rule.id}
+	}
+
+	children := []canonicalNode{}
+	for _, child := range node.children {
+		cn := f.canonicalNode(child)
+		if cn.op == node.op {
+			children = append(children, cn.children...)
+		} else {
+			children = append(children, cn)
+		}
+	}
+
+	slices.SortFunc(children, func(a, b canonicalNode) int { return strings.Compare(a.id, b.id) })
+	children = slices.CompactFunc(children, func(a, b canonicalNode) bool { return a.id == b.id })
+	if len(children) == 1 {
+		return children[0]
+	}
+
+	ids := make([]string, len(children))
+	for i := range children {
+		ids[i] = children[i].id
+	}
+
+	op := "and"
+	if node.op == logicalOr {
+		op = "or"
+	}
+
+	return canonicalNode{op: node.op, children: children, id: op + "(" + strings.Join(ids, ";") + ")"}
+}
+
+func (f *Filter) canonicalRule(node ruleNode) canonicalRule {
+	key := f.keys[node.key]
+	rule := key.rules[node.rule]
+
+	var values []any
+	switch key.Type {
+	case ruleTypeInt:
+		values = ruleValues(rule, f.intVals)
+	case ruleTypeUint:
+		values = ruleValues(rule, f.uintVals)
+	case ruleTypeFloat:
+		values = ruleValues(rule, f.floatVals)
+	case ruleTypeString:
+		values = ruleValues(rule, f.stringVals)
+	case ruleTypeTime:
+		values = ruleValues(rule, f.timeVals)
+	}
+
+	// Rules with no values can be of any type
+	ruleType := key.Type
+	if len(values) == 0 {
+		ruleType = ruleTypeNone
+	}
+
+	if rule.Comparasion == ComparasionEquals || rule.Comparasion == ComparasionNotEquals {
+		slices.SortFunc(values, compareValues)
+		values = slices.CompactFunc(values, func(a, b any) bool { return canonicalValue(a) == canonicalValue(b) })
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strconv.Quote(key.key))
+	sb.WriteString(" " + jsonTypes[ruleType])
+
+	// Unknown comparasions are written as their number, so they still can't
+	// be confused with the others
+	comparison, ok := jsonComparison(rule.Comparasion)
+	if !ok {
+		comparison = rule.Comparasion.identifier()
+	}
+	sb.WriteString(" " + comparison)
+	for _, value := range values {
+		sb.WriteString(" " + canonicalValue(value))
+	}
+
+	return canonicalRule{key.key, ruleType, rule.Comparasion, values, sb.String()}
+}
+
+// walk calls fn for the rule of each leaf of the node.
+func (cn canonicalNode) walk(fn func(rule canonicalRule)) {
+	if cn.op == logicalLeaf {
+		fn(cn.rule)
+		return
+	}
+
+	for _, child := range cn.children {
+		child.walk(fn)
+	}
+}
+
+// ruleNode converts the node back into an expression, with the leaves of each
+// rule identifier.
+func (cn canonicalNode) ruleNode(leaves map[string]ruleNode) ruleNode {
+	if cn.op == logicalLeaf {
+		return leaves[cn.id]
+	}
+
+	node := ruleNode{op: cn.op, children: make([]ruleNode, len(cn.children))}
+	for i := range cn.children {
+		node.children[i] = cn.children[i].ruleNode(leaves)
+	}
+
+	return node
+}
+
+func (cn canonicalNode) hasOr() bool {
+	if cn.op == logicalOr {
+		return true
+	}
+
+	return slices.ContainsFunc(cn.children, canonicalNode.hasOr)
+}
+
+func (f *Filter) addCanonicalRule(rule canonicalRule) {
+	switch rule.Type {
+	case ruleTypeInt:
+		f.AddInt(rule.key, valuesOf[int](rule.values), rule.comparasion)
+	case ruleTypeUint:
+		f.AddUint(rule.key, valuesOf[uint](rule.values), rule.comparasion)
+	case ruleTypeFloat:
+		f.AddFloat(rule.key, valuesOf[float64](rule.values), rule.comparasion)
+	case ruleTypeString:
+		f.AddString(rule.key, valuesOf[string](rule.values), rule.comparasion)
+	case ruleTypeTime:
+		f.AddTime(rule.key, valuesOf[time.Time](rule.values), rule.comparasion)
+	default:
+		f.AddNull(rule.key, rule.comparasion)
+	}
+}
+
+func valuesOf[T Primitive](values []any) []T {
+	result := make([]T, len(values))
+	for i := range values {
+		result[i] = values[i].(T)
+	}
+
+	return result
+}
+
+// compareValues compares two values of the same type, values that are equal
+// but written differently (like times in different locations) are compared
+// by how they are written.
+func compareValues(a, b any) int {
+	var c int
+	switch a := a.(type) {
+	case int:
+		c = cmp.Compare(a, b.(int))
+	case uint:
+		c = cmp.Compare(a, b.(uint))
+	case float64:
+		c = cmp.Compare(a, b.(float64))
+	case time.Time:
+		c = a.Compare(b.(time.Time))
+	}
+
+	return cmp.Or(c, strings.Compare(canonicalValue(a), canonicalValue(b)))
+}
+
+// canonicalValue writes the value without ambiguity, quoting strings.
+func canonicalValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
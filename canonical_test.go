@@ -0,0 +1,126 @@
+package qfl_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleFilter_Fingerprint() {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddString("role")
+
+	a, _ := parser.Parse(map[string]string{"age": "lt!60|gt!20", "role": "eq!b,a"})
+	b, _ := parser.Parse(map[string]string{"role": "eq!a,b,a", "age": "gt!20|lt!60"})
+	c, _ := parser.Parse(map[string]string{"role": "eq!a,b", "age": "gt!20|lt!50"})
+
+	fmt.Println(a.Fingerprint() == b.Fingerprint())
+	fmt.Println(a.Fingerprint() == c.Fingerprint())
+	// Output:
+	// true
+	// false
+}
+
+func TestFilterCanonical(t *testing.T) {
+	parser := qfl.Parser{ExpressionKey: "q"}
+	parser.AddInt("age")
+	parser.AddUint("id")
+	parser.AddString("name")
+
+	equal := [][2]map[string]string{
+		{{"age": "gt!20|lt!60"}, {"age": "lt!60|gt!20"}},
+		{{"age": "eq!3,1,2"}, {"age": "eq!1,2,3,2"}},
+		{{"age": "ne!3,1", "name": "a"}, {"name": "eq!a", "age": "ne!1,3"}},
+		{{"age": "gt!1~lt!0"}, {"age": "lt!0~gt!1"}},
+		{{"age": "(gt!1~lt!0)|ne!5"}, {"age": "ne!5|(lt!0~(gt!1))"}},
+		{{"q": "name:a or (id:1 and age:2)"}, {"q": "(age:2 and id:1) or name:a"}},
+		{{"q": "age:1 or (age:2 or age:3)"}, {"age": "eq!3~eq!1~eq!2"}},
+		{{"age": "gt!1", "q": "age:gt!1"}, {"age": "gt!1"}},
+		{{"name": "is!null"}, {"name": "is!null|is!null"}},
+	}
+
+	for _, c := range equal {
+		a, err := parser.Parse(c[0])
+		assert.NoError(t, err, c)
+
+		b, err := parser.Parse(c[1])
+		assert.NoError(t, err, c)
+
+		assert.Equal(t, a.Canonical(), b.Canonical(), c)
+		assert.Equal(t, a.Fingerprint(), b.Fingerprint(), c)
+		assert.Equal(t, a.Fingerprint(), a.Canonical().Fingerprint(), c)
+	}
+
+	different := [][2]map[string]string{
+		{{"age": "gt!20|lt!60"}, {"age": "gt!20~lt!60"}},
+		{{"age": "eq!1,2"}, {"age": "eq!1|eq!2"}},
+		{{"age": "1"}, {"id": "1"}},
+		{{"name": "a"}, {"name": "eq!a\\,b"}},
+		{{"q": "name:a or (id:1 and age:2)"}, {"q": "(name:a or id:1) and age:2"}},
+	}
+
+	for _, c := range different {
+		a, err := parser.Parse(c[0])
+		assert.NoError(t, err, c)
+
+		b, err := parser.Parse(c[1])
+		assert.NoError(t, err, c)
+
+		assert.NotEqual(t, a.Fingerprint(), b.Fingerprint(), c)
+	}
+}
+
+func TestFilterCanonicalValues(t *testing.T) {
+	a := qfl.Filter{}
+	a.AddString("name", []string{"b", "a"}, qfl.ComparasionEquals)
+	a.AddInt("age", []int{20}, qfl.ComparasionMoreThan)
+	a.AddNull("deleted", qfl.ComparasionIsNull)
+
+	b := qfl.Filter{}
+	b.AddNull("deleted", qfl.ComparasionIsNull)
+	b.AddInt("age", []int{20}, qfl.ComparasionMoreThan)
+	b.AddString("name", []string{"a", "b"}, qfl.ComparasionEquals)
+
+	canonical := a.Canonical()
+	assert.Equal(t, b.Canonical(), canonical)
+	assert.Equal(t, []qfl.FilterRule[string]{
+		{Comparasion: qfl.ComparasionEquals, Values: []string{"a", "b"}},
+	}, canonical.GetString("name"))
+
+	// The sort order changes the results, so it isn't canonicalized
+	a.AddSort(qfl.SortField{Key: "age"}, qfl.SortField{Key: "name"})
+	b.AddSort(qfl.SortField{Key: "name"}, qfl.SortField{Key: "age"})
+	assert.NotEqual(t, a.Fingerprint(), b.Fingerprint())
+
+	b = a
+	b.SetPage(&qfl.Page{Limit: 10})
+	assert.NotEqual(t, a.Fingerprint(), b.Fingerprint())
+
+	// Times are compared by how they are written, as locations can change how
+	// they are converted
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := qfl.Filter{}
+	c.AddTime("created", []time.Time{utc, utc.In(time.FixedZone("", 3600))}, qfl.ComparasionEquals)
+
+	d := qfl.Filter{}
+	d.AddTime("created", []time.Time{utc.In(time.FixedZone("", 3600)), utc, utc}, qfl.ComparasionEquals)
+	assert.Equal(t, c.Fingerprint(), d.Fingerprint())
+	assert.Len(t, d.Canonical().GetTime("created")[0].Values, 2)
+}
+
+func TestFilterCanonicalUnknownComparasion(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{1}, qfl.ComparasionType(99))
+
+	other := qfl.Filter{}
+	other.AddInt("age", []int{1}, qfl.ComparasionType(98))
+
+	assert.NotEmpty(t, filter.Fingerprint())
+	assert.Equal(t, filter.Fingerprint(), filter.Canonical().Fingerprint())
+	assert.NotEqual(t, filter.Fingerprint(), other.Fingerprint())
+}
@@ -0,0 +1,283 @@
+package qfl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor is the position of a row on the sorted results, used to fetch the
+// rows after or before it with keyset pagination.
+type Cursor struct {
+	Values   []any // values of the sort keys on the row, in order of the sort fields
+	Backward bool  // fetch the rows before the position, with the sort reversed
+}
+
+// SetCursor sets the position of the row to paginate from, the filter must
+// have one sort field for each of its values.
+func (f *Filter) SetCursor(cursor *Cursor) {
+	f.cursor = cursor
+}
+
+// GetCursor returns the position of the row to paginate from, or nil if the
+// results start from the beginning.
+func (f *Filter) GetCursor() *Cursor {
+	return f.cursor
+}
+
+// NextCursor returns the cursor of the rows after the record, which should be
+// the last row of the current results. The record is read the same way as
+// `Filter.Match`, and it must not be null on the sort keys.
+func (p Parser) NextCursor(f *Filter, record any) (string, error) {
+	return p.encodeCursor(f, record, false)
+}
+
+// PrevCursor returns the cursor of the rows before the record, which should be
+// the first row of the current results. Rows before the cursor are fetched
+// with the sort reversed, so they must be reversed back after fetching them.
+func (p Parser) PrevCursor(f *Filter, record any) (string, error) {
+	return p.encodeCursor(f, record, true)
+}
+
+// cursorPayload is the content of an encoded cursor. It keeps the sort fields
+// it was made for, so it can't be used with a different sort.
+type cursorPayload struct {
+	Sort     string        `json:"s"`
+	Backward bool          `json:"b,omitempty"`
+	Values   []cursorValue `json:"v"`
+}
+
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// encodeCursor encodes the values of the sort keys on the record, followed by
+// their signature.
+func (p Parser) encodeCursor(f *Filter, record any, backward bool) (string, error) {
+	if len(p.CursorSecret) == 0 {
+		return "", fmt.Errorf("field `CursorSecret` is empty")
+	} else if len(f.sort) == 0 {
+		return "", fmt.Errorf("filter has no sort fields")
+	}
+
+	rv, err := recordValue(record)
+	if err != nil {
+		return "", err
+	}
+
+	payload := cursorPayload{Sort: sortString(f.sort), Backward: backward}
+	for _, field := range f.sort {
+		value, err := lookupField(rv, field.Key)
+		if err != nil {
+			return "", err
+		}
+
+		cv, err := newCursorValue(field.Key, value)
+		if err != nil {
+			return "", err
+		}
+
+		payload.Values = append(payload.Values, cv)
+	}
+
+	return p.signPayload(payload)
+}
+
+// signPayload encodes the payload of the cursor followed by its signature.
+func (p Parser) signPayload(payload cursorPayload) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + p.signCursor(encoded), nil
+}
+
+// parseCursor verifies the signature of the cursor and decodes its values,
+// which must be for the same sort fields of the filter.
+func (p Parser) parseCursor(value string, sort []SortField) (*Cursor, *ParseError) {
+	invalid := &ParseError{Kind: ParseErrorInvalidCursor}
+
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(p.signCursor(encoded))) {
+		return nil, invalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+
+	payload := cursorPayload{}
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Sort != sortString(sort) {
+		return nil, invalid
+	}
+
+	cursor := &Cursor{Backward: payload.Backward}
+	for _, cv := range payload.Values {
+		value, err := cv.parse()
+		if err != nil {
+			return nil, invalid
+		}
+
+		cursor.Values = append(cursor.Values, value)
+	}
+
+	if len(cursor.Values) != len(sort) {
+		return nil, invalid
+	}
+
+	return cursor, nil
+}
+
+func (p Parser) signCursor(encoded string) string {
+	mac := hmac.New(sha256.New, p.CursorSecret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newCursorValue(key string, field reflect.Value) (cursorValue, error) {
+	switch {
+	case !field.IsValid():
+		return cursorValue{}, fmt.Errorf("key `%s` is null, cursors can't have null values", key)
+	case field.CanInt():
+		return cursorValue{"int", strconv.FormatInt(field.Int(), 10)}, nil
+	case field.CanUint():
+		return cursorValue{"uint", strconv.FormatUint(field.Uint(), 10)}, nil
+	case field.CanFloat():
+		return cursorValue{"float", strconv.FormatFloat(field.Float(), 'g', -1, 64)}, nil
+	case field.Kind() == reflect.String:
+		return cursorValue{"string", field.String()}, nil
+	}
+
+	if t, ok := field.Interface().(time.Time); ok {
+		return cursorValue{"time", t.Format(time.RFC3339Nano)}, nil
+	}
+
+	return cursorValue{}, fmt.Errorf("value of type `%s` for key `%s` can't be used on cursors", field.Type(), key)
+}
+
+func (cv cursorValue) parse() (any, error) {
+	switch cv.Type {
+	case "int":
+		n, err := strconv.ParseInt(cv.Value, 10, 0)
+		return int(n), err
+	case "uint":
+		n, err := strconv.ParseUint(cv.Value, 10, 0)
+		return uint(n), err
+	case "float":
+		return strconv.ParseFloat(cv.Value, 64)
+	case "string":
+		return cv.Value, nil
+	case "time":
+		return time.Parse(time.RFC3339Nano, cv.Value)
+	}
+
+	return nil, fmt.Errorf("cursor value has unknown type `%s`", cv.Type)
+}
+
+// sortString writes the sort fields with the syntax of the sort variable.
+func sortString(fields []SortField) string {
+	var sb strings.Builder
+	for i, field := range fields {
+		if i != 0 {
+			sb.WriteRune(',')
+		}
+
+		if field.Descending {
+			sb.WriteRune('-')
+		}
+		sb.WriteString(field.Key)
+
+		switch field.Nulls {
+		case SortNullsFirst:
+			sb.WriteString("!first")
+		case SortNullsLast:
+			sb.WriteString("!last")
+		}
+	}
+
+	return sb.String()
+}
+
+// cursorCondition returns the condition that selects the rows after the
+// cursor, in the order of the sort fields, or false if there's no cursor.
+//
+// Rows are compared by their values on all sort keys, using row values when
+// all of them are sorted in the same direction and the dialect supports it.
+func (w conditionWriter) cursorCondition() (sqlCondition, bool, error) {
+	cursor := w.filter.cursor
+	if cursor == nil {
+		return sqlCondition{}, false, nil
+	} else if len(w.filter.sort) == 0 {
+		return sqlCondition{}, false, fmt.Errorf("filter has a cursor, but no sort fields")
+	} else if len(cursor.Values) != len(w.filter.sort) {
+		return sqlCondition{}, false, fmt.Errorf("cursor has %d values, but the filter has %d sort fields", len(cursor.Values), len(w.filter.sort))
+	}
+
+	sort := w.filter.sort
+	columns := make([]string, len(sort))
+	operators := make([]string, len(sort))
+	sameDirection := true
+	for i, field := range sort {
+		if _, ok := w.keys[field.Key]; !ok {
+			return sqlCondition{}, false, fmt.Errorf("key `%s` isn't mapped to a column", field.Key)
+		}
+
+		columns[i] = w.column(field.Key)
+		operators[i] = " > ?"
+		if field.Descending != cursor.Backward {
+			operators[i] = " < ?"
+		}
+
+		sameDirection = sameDirection && field.Descending == sort[0].Descending
+	}
+
+	rowValues := false
+	if d, ok := w.dialect.(RowValueDialect); ok {
+		rowValues = d.RowValues()
+	}
+
+	var sb strings.Builder
+	if len(sort) == 1 {
+		sb.WriteString(columns[0] + operators[0])
+		return sqlCondition{sb.String(), cursor.Values}, true, nil
+	} else if rowValues && sameDirection {
+		sb.WriteRune('(')
+		sb.WriteString(strings.Join(columns, ", "))
+		sb.WriteString(")" + operators[0][:2] + " (")
+		sb.WriteString(strings.Repeat("?, ", len(columns)-1))
+		sb.WriteString("?)")
+		return sqlCondition{sb.String(), cursor.Values}, true, nil
+	}
+
+	// Rows after the cursor are the ones that are equal on the first keys and
+	// come after it on the next one
+	args := []any{}
+	for i := range sort {
+		if i != 0 {
+			sb.WriteString(" OR ")
+		}
+
+		sb.WriteRune('(')
+		for j := range i {
+			sb.WriteString(columns[j])
+			sb.WriteString(" = ? AND ")
+			args = append(args, cursor.Values[j])
+		}
+
+		sb.WriteString(columns[i] + operators[i])
+		sb.WriteRune(')')
+		args = append(args, cursor.Values[i])
+	}
+
+	return sqlCondition{sb.String(), args}, true, nil
+}
@@ -0,0 +1,148 @@
+package qfl_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParser_NextCursor() {
+	parser := qfl.Parser{SortKey: "sort", CursorKey: "cursor", CursorSecret: []byte("secret")}
+	parser.AddSort("age", "id")
+
+	filter, err := parser.Parse(map[string]string{"sort": "-age,-id"})
+	if err != nil {
+		// Treat error...
+	}
+
+	// Last row of the current page
+	last := map[string]any{"id": 42, "age": 30}
+	cursor, err := parser.NextCursor(filter, last)
+	if err != nil {
+		// Treat error...
+	}
+
+	filter, err = parser.Parse(map[string]string{"sort": "-age,-id", "cursor": cursor})
+	if err != nil {
+		// Treat error...
+	}
+
+	query := qfl.SQLSelect{
+		Filter:  *filter,
+		Keys:    map[string]string{"age": "age", "id": "id"},
+		Dialect: qfl.PostgresDialect{},
+	}
+
+	sql, params, err := query.Select("users").Page(20, 0).Build()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(sql)
+	fmt.Println(params)
+	// Output:
+	// SELECT * FROM users
+	// WHERE (age, id) < ($1, $2)
+	// ORDER BY age DESC, id DESC
+	// LIMIT 20 OFFSET 0
+	// [30 42]
+}
+
+func TestCursorBackward(t *testing.T) {
+	parser := qfl.Parser{SortKey: "sort", CursorKey: "cursor", CursorSecret: []byte("secret")}
+	parser.AddSort("name", "created_at", "id")
+
+	sort := "name!last,-created_at,id"
+	filter, err := parser.Parse(map[string]string{"sort": sort})
+	assert.NoError(t, err)
+
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	first := struct {
+		ID        uint      `qfl:"id"`
+		Name      string    `qfl:"name"`
+		CreatedAt time.Time `qfl:"created_at"`
+	}{7, "Rob", created}
+
+	cursor, err := parser.PrevCursor(filter, &first)
+	assert.NoError(t, err)
+
+	filter, err = parser.Parse(map[string]string{"sort": sort, "cursor": cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, &qfl.Cursor{Values: []any{"Rob", created, uint(7)}, Backward: true}, filter.GetCursor())
+
+	builder := qfl.SQLBuilder{
+		Filter:  *filter,
+		Keys:    map[string]string{"name": "name", "created_at": "created_at", "id": "id"},
+		Dialect: qfl.SQLServerDialect{},
+	}
+
+	params, err := builder.Where()
+	assert.NoError(t, err)
+	assert.NoError(t, builder.Sort())
+	assert.Equal(t, "WHERE (name < @p1) OR (name = @p2 AND created_at > @p3) OR "+
+		"(name = @p4 AND created_at = @p5 AND id < @p6)\n"+
+		"ORDER BY CASE WHEN name IS NULL THEN 0 ELSE 1 END, name DESC, created_at ASC, id DESC\n",
+		builder.Builder.String())
+	assert.Equal(t, []any{"Rob", "Rob", created, "Rob", created, uint(7)}, params)
+}
+
+func TestCursorInvalid(t *testing.T) {
+	parser := qfl.Parser{SortKey: "sort", CursorKey: "cursor", CursorSecret: []byte("secret")}
+	parser.AddSort("id", "name")
+
+	filter, err := parser.Parse(map[string]string{"sort": "id"})
+	assert.NoError(t, err)
+
+	cursor, err := parser.NextCursor(filter, map[string]any{"id": 1})
+	assert.NoError(t, err)
+
+	other := qfl.Parser{SortKey: "sort", CursorKey: "cursor", CursorSecret: []byte("other")}
+	other.AddSort("id", "name")
+
+	cases := []struct {
+		parser qfl.Parser
+		kv     map[string]string
+	}{
+		{parser, map[string]string{"sort": "id", "cursor": cursor[1:]}},
+		{parser, map[string]string{"sort": "id", "cursor": "garbage"}},
+		{parser, map[string]string{"sort": "-id", "cursor": cursor}},
+		{parser, map[string]string{"cursor": cursor}},
+		{other, map[string]string{"sort": "id", "cursor": cursor}},
+	}
+
+	for _, c := range cases {
+		_, err := c.parser.Parse(c.kv)
+
+		var pe *qfl.ParseError
+		if assert.True(t, errors.As(err, &pe), c.kv) {
+			assert.Equal(t, "cursor", pe.Key)
+			assert.Equal(t, qfl.ParseErrorInvalidCursor, pe.Kind)
+		}
+	}
+
+	_, err = parser.NextCursor(filter, map[string]any{"id": nil})
+	assert.Error(t, err)
+
+	_, err = qfl.Parser{CursorKey: "cursor"}.Parse(map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestCursorWithoutSort(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.SetCursor(&qfl.Cursor{})
+
+	query := qfl.SQLSelect{Filter: filter, Keys: map[string]string{"id": "id"}}
+	query.Select("users")
+
+	_, _, err := query.Build()
+	assert.EqualError(t, err, "filter has a cursor, but no sort fields")
+
+	builder := qfl.SQLBuilder{Filter: filter, Keys: map[string]string{"id": "id"}}
+	_, err = builder.Where()
+	assert.EqualError(t, err, "filter has a cursor, but no sort fields")
+}
@@ -0,0 +1,370 @@
+package qfl
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Dialect controls the syntax of the SQL that differs between databases.
+type Dialect interface {
+	// Placeholder returns the placeholder of the n-th parameter, starting at 1.
+	Placeholder(n int) string
+
+	// QuoteIdentifier quotes a table or column name, qualified names are
+	// quoted part by part.
+	QuoteIdentifier(name string) string
+
+	// Like returns the operator used to compare strings with a pattern.
+	Like() string
+
+	// Paginate returns the clause that skips offset rows and limits the
+	// amount of rows of the query.
+	Paginate(limit, offset uint64) string
+
+	// Bool returns a condition that is always true or false.
+	Bool(value bool) string
+}
+
+// ArgDialect is implemented by dialects that need to change the arguments
+// passed along with the query, like named parameters.
+type ArgDialect interface {
+	Dialect
+
+	// Arg returns the argument of the n-th parameter, starting at 1.
+	Arg(n int, value any) any
+}
+
+// ArrayDialect is implemented by dialects that can pass a list of values as a
+// single array parameter, instead of one parameter for each value.
+type ArrayDialect interface {
+	Dialect
+
+	// Array returns the parameter for the slice of values, or false if the
+	// list should be written with one parameter for each value.
+	Array(values any) (any, bool)
+}
+
+// ChunkDialect is implemented by dialects that limit the amount of values of
+// an IN list, splitting bigger lists into groups.
+type ChunkDialect interface {
+	Dialect
+
+	// ListLimit returns the maximum amount of values of an IN list, zero
+	// means there's no limit.
+	ListLimit() int
+}
+
+// NullsOrderDialect is implemented by dialects that support NULLS FIRST and
+// NULLS LAST when sorting, the other ones have it emulated.
+type NullsOrderDialect interface {
+	Dialect
+
+	// NullsOrder tells if NULLS FIRST and NULLS LAST are supported.
+	NullsOrder() bool
+}
+
+// RowValueDialect is implemented by dialects that support comparing row
+// values, like `(a, b) > (1, 2)`, the other ones have it expanded.
+type RowValueDialect interface {
+	Dialect
+
+	// RowValues tells if row values can be compared.
+	RowValues() bool
+}
+
+// PostgresDialect uses `$1` placeholders and double quotes on identifiers.
+type PostgresDialect struct {
+	CaseInsensitive bool // use ILIKE instead of LIKE
+
+	// Pass lists as a single array parameter, using `= ANY($1)` instead of IN
+	ArrayParameters bool
+
+	// Wraps the slice of array parameters, for drivers that doesn't support
+	// slices directly, like `pq.Array`
+	WrapArray func(values any) any
+}
+
+func (PostgresDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (PostgresDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`, `"`)
+}
+
+func (d PostgresDialect) Like() string {
+	if d.CaseInsensitive {
+		return "ILIKE"
+	}
+
+	return "LIKE"
+}
+
+func (PostgresDialect) Paginate(limit, offset uint64) string {
+	return limitOffset(limit, offset)
+}
+
+func (PostgresDialect) Bool(value bool) string {
+	if value {
+		return "TRUE"
+	}
+
+	return "FALSE"
+}
+
+func (PostgresDialect) NullsOrder() bool {
+	return true
+}
+
+func (PostgresDialect) RowValues() bool {
+	return true
+}
+
+func (d PostgresDialect) Array(values any) (any, bool) {
+	if !d.ArrayParameters {
+		return nil, false
+	} else if d.WrapArray != nil {
+		return d.WrapArray(values), true
+	}
+
+	return values, true
+}
+
+// MySQLDialect uses `?` placeholders and backticks on identifiers.
+type MySQLDialect struct {
+	MaxListSize int // split IN lists bigger than it into groups, unlimited if zero
+}
+
+func (MySQLDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (MySQLDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "`", "`")
+}
+
+func (MySQLDialect) Like() string {
+	return "LIKE"
+}
+
+func (MySQLDialect) Paginate(limit, offset uint64) string {
+	return limitOffset(limit, offset)
+}
+
+func (MySQLDialect) Bool(value bool) string {
+	if value {
+		return "TRUE"
+	}
+
+	return "FALSE"
+}
+
+func (d MySQLDialect) ListLimit() int {
+	return d.MaxListSize
+}
+
+func (MySQLDialect) RowValues() bool {
+	return true
+}
+
+// SQLiteDialect uses `?` placeholders and double quotes on identifiers.
+type SQLiteDialect struct {
+	MaxListSize int // split IN lists bigger than it into groups, unlimited if zero
+}
+
+func (SQLiteDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (SQLiteDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`, `"`)
+}
+
+func (SQLiteDialect) Like() string {
+	return "LIKE"
+}
+
+func (SQLiteDialect) Paginate(limit, offset uint64) string {
+	return limitOffset(limit, offset)
+}
+
+func (SQLiteDialect) Bool(value bool) string {
+	if value {
+		return "1"
+	}
+
+	return "0"
+}
+
+func (d SQLiteDialect) ListLimit() int {
+	return d.MaxListSize
+}
+
+func (SQLiteDialect) NullsOrder() bool {
+	return true
+}
+
+func (SQLiteDialect) RowValues() bool {
+	return true
+}
+
+// SQLServerDialect uses `@p1` placeholders and brackets on identifiers. Note
+// that pagination requires the query to be ordered.
+type SQLServerDialect struct{}
+
+func (SQLServerDialect) Placeholder(n int) string {
+	return "@p" + strconv.Itoa(n)
+}
+
+func (SQLServerDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "[", "]")
+}
+
+func (SQLServerDialect) Like() string {
+	return "LIKE"
+}
+
+func (SQLServerDialect) Paginate(limit, offset uint64) string {
+	return offsetFetch(limit, offset)
+}
+
+func (SQLServerDialect) Bool(value bool) string {
+	if value {
+		return "1 = 1"
+	}
+
+	return "1 = 0"
+}
+
+// OracleDialect uses `:1` placeholders and double quotes on identifiers. Note
+// that pagination requires Oracle 12c or newer.
+type OracleDialect struct{}
+
+func (OracleDialect) Placeholder(n int) string {
+	return ":" + strconv.Itoa(n)
+}
+
+func (OracleDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`, `"`)
+}
+
+func (OracleDialect) Like() string {
+	return "LIKE"
+}
+
+func (OracleDialect) Paginate(limit, offset uint64) string {
+	return offsetFetch(limit, offset)
+}
+
+func (OracleDialect) Bool(value bool) string {
+	if value {
+		return "1 = 1"
+	}
+
+	return "1 = 0"
+}
+
+func (OracleDialect) NullsOrder() bool {
+	return true
+}
+
+// NamedDialect changes the placeholders of a dialect into named parameters,
+// like `@p1` or `:p1`, passing the arguments as `sql.NamedArg`. The optional
+// interfaces of the wrapped dialect are forwarded to it.
+type NamedDialect struct {
+	Dialect
+	Prefix string // symbol before the name, like `@` or `:`
+	Name   string // name of the parameters before their number, defaults to `p`
+}
+
+func (d NamedDialect) Placeholder(n int) string {
+	return d.Prefix + d.name(n)
+}
+
+func (d NamedDialect) Arg(n int, value any) any {
+	return sql.Named(d.name(n), value)
+}
+
+func (d NamedDialect) Array(values any) (any, bool) {
+	if inner, ok := d.Dialect.(ArrayDialect); ok {
+		return inner.Array(values)
+	}
+
+	return nil, false
+}
+
+func (d NamedDialect) ListLimit() int {
+	if inner, ok := d.Dialect.(ChunkDialect); ok {
+		return inner.ListLimit()
+	}
+
+	return 0
+}
+
+func (d NamedDialect) NullsOrder() bool {
+	if inner, ok := d.Dialect.(NullsOrderDialect); ok {
+		return inner.NullsOrder()
+	}
+
+	return false
+}
+
+func (d NamedDialect) RowValues() bool {
+	if inner, ok := d.Dialect.(RowValueDialect); ok {
+		return inner.RowValues()
+	}
+
+	return false
+}
+
+func (d NamedDialect) name(n int) string {
+	if d.Name == "" {
+		return "p" + strconv.Itoa(n)
+	}
+
+	return d.Name + strconv.Itoa(n)
+}
+
+// dialectFromFormat returns the dialect equivalent to the placeholder format.
+func dialectFromFormat(format SQLPlaceholderFormat) Dialect {
+	if format == SQLPlaceholderDollarSign {
+		return PostgresDialect{}
+	}
+
+	return MySQLDialect{}
+}
+
+// bindArg returns the argument of the n-th parameter for the dialect.
+func bindArg(dialect Dialect, n int, value any) any {
+	if d, ok := dialect.(ArgDialect); ok {
+		return d.Arg(n, value)
+	}
+
+	return value
+}
+
+// quoteIdentifier quotes each part of a qualified name, escaping the closing
+// quote by repeating it.
+func quoteIdentifier(name, open, close string) string {
+	var sb strings.Builder
+	for i, part := range strings.Split(name, ".") {
+		if i != 0 {
+			sb.WriteRune('.')
+		}
+
+		sb.WriteString(open)
+		sb.WriteString(strings.ReplaceAll(part, close, close+close))
+		sb.WriteString(close)
+	}
+
+	return sb.String()
+}
+
+func limitOffset(limit, offset uint64) string {
+	return "LIMIT " + strconv.FormatUint(limit, 10) + " OFFSET " + strconv.FormatUint(offset, 10)
+}
+
+func offsetFetch(limit, offset uint64) string {
+	return "OFFSET " + strconv.FormatUint(offset, 10) + " ROWS FETCH NEXT " + strconv.FormatUint(limit, 10) + " ROWS ONLY"
+}
@@ -0,0 +1,221 @@
+package qfl_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleDialect() {
+	filter := qfl.Filter{}
+	filter.AddString("name", []string{"Rob%"}, qfl.ComparasionLike)
+	filter.AddUint("age", []uint{18, 65}, qfl.ComparasionBetween)
+
+	builder := qfl.SQLBuilder{
+		Filter: filter,
+		Keys: map[string]string{
+			"name": "u.name",
+			"age":  "u.age",
+		},
+		Dialect:          qfl.SQLServerDialect{},
+		QuoteIdentifiers: true,
+	}
+
+	builder.Select("users u", "u.id", "u.name")
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+	builder.Order("ASC", "u.id")
+	builder.Page(20, 2)
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// SELECT u.id, u.name FROM users u
+	// WHERE [u].[name] LIKE @p1 AND [u].[age] BETWEEN @p2 AND @p3
+	// ORDER BY u.id ASC
+	// OFFSET 40 ROWS FETCH NEXT 20 ROWS ONLY
+	//
+	// [Rob% 18 65]
+}
+
+func TestDialects(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddString("name", []string{"rob%"}, qfl.ComparasionNotLike)
+	filter.AddInt("id", []int{1, 2}, qfl.ComparasionEquals)
+	filter.AddInt("group", []int{}, qfl.ComparasionEquals)
+
+	cases := []struct {
+		dialect qfl.Dialect
+		where   string
+		page    string
+	}{
+		{
+			qfl.PostgresDialect{CaseInsensitive: true},
+			`WHERE "name" NOT ILIKE $1 AND "id" IN ($2,$3) AND FALSE`,
+			"LIMIT 10 OFFSET 10",
+		},
+		{
+			qfl.MySQLDialect{},
+			"WHERE `name` NOT LIKE ? AND `id` IN (?,?) AND FALSE",
+			"LIMIT 10 OFFSET 10",
+		},
+		{
+			qfl.SQLiteDialect{},
+			`WHERE "name" NOT LIKE ? AND "id" IN (?,?) AND 0`,
+			"LIMIT 10 OFFSET 10",
+		},
+		{
+			qfl.SQLServerDialect{},
+			`WHERE [name] NOT LIKE @p1 AND [id] IN (@p2,@p3) AND 1 = 0`,
+			"OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY",
+		},
+		{
+			qfl.OracleDialect{},
+			`WHERE "name" NOT LIKE :1 AND "id" IN (:2,:3) AND 1 = 0`,
+			"OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY",
+		},
+	}
+
+	for _, c := range cases {
+		builder := qfl.SQLBuilder{
+			Filter:           filter,
+			Keys:             map[string]string{"name": "name", "id": "id", "group": "group"},
+			Dialect:          c.dialect,
+			QuoteIdentifiers: true,
+		}
+
+		params, err := builder.Where()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"rob%", 1, 2}, params)
+
+		builder.Page(10, 1)
+		assert.Equal(t, c.where+"\n"+c.page+"\n", builder.Builder.String())
+	}
+}
+
+func TestNamedDialect(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("id", []int{1, 2}, qfl.ComparasionNotEquals)
+
+	builder := qfl.SQLBuilder{
+		Filter:  filter,
+		Keys:    map[string]string{"id": "id"},
+		Dialect: qfl.NamedDialect{Dialect: qfl.PostgresDialect{}, Prefix: "@", Name: "arg"},
+	}
+
+	params, err := builder.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE id NOT IN (@arg1,@arg2)\n", builder.Builder.String())
+	assert.Equal(t, []any{sql.Named("arg1", 1), sql.Named("arg2", 2)}, params)
+}
+
+func TestNamedDialectForwarding(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("id", []int{1, 2, 3}, qfl.ComparasionEquals)
+	filter.AddSort(qfl.SortField{Key: "id", Nulls: qfl.SortNullsLast})
+
+	keys := map[string]string{"id": "id"}
+	builder := qfl.SQLBuilder{
+		Filter:  filter,
+		Keys:    keys,
+		Dialect: qfl.NamedDialect{Dialect: qfl.PostgresDialect{ArrayParameters: true}, Prefix: "@"},
+	}
+
+	params, err := builder.Where()
+	assert.NoError(t, err)
+	assert.NoError(t, builder.Sort())
+	assert.Equal(t, "WHERE id = ANY(@p1)\nORDER BY id ASC NULLS LAST\n", builder.Builder.String())
+	assert.Equal(t, []any{sql.Named("p1", []int{1, 2, 3})}, params)
+
+	builder = qfl.SQLBuilder{
+		Filter:  filter,
+		Keys:    keys,
+		Dialect: qfl.NamedDialect{Dialect: qfl.MySQLDialect{MaxListSize: 2}, Prefix: ":"},
+	}
+
+	_, err = builder.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE (id IN (:p1,:p2) OR id IN (:p3))\n", builder.Builder.String())
+
+	// Dialects without the interfaces keep their default behavior
+	named := qfl.NamedDialect{Dialect: qfl.SQLServerDialect{}}
+	_, ok := named.Array([]int{1})
+	assert.False(t, ok)
+	assert.Zero(t, named.ListLimit())
+	assert.False(t, named.NullsOrder())
+	assert.False(t, named.RowValues())
+}
+
+func TestQuoteIdentifierEscapes(t *testing.T) {
+	assert.Equal(t, `"a""b"."c"`, qfl.PostgresDialect{}.QuoteIdentifier(`a"b.c`))
+	assert.Equal(t, "`a``b`", qfl.MySQLDialect{}.QuoteIdentifier("a`b"))
+	assert.Equal(t, "[a]]b]", qfl.SQLServerDialect{}.QuoteIdentifier("a]b"))
+}
+
+func TestPostgresArrayParameters(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("id", []int{1, 2, 3}, qfl.ComparasionEquals)
+	filter.AddString("name", []string{"a", "b"}, qfl.ComparasionNotEquals)
+	filter.AddInt("age", []int{20}, qfl.ComparasionEquals)
+
+	builder := qfl.SQLBuilder{
+		Filter:  filter,
+		Keys:    map[string]string{"id": "id", "name": "name", "age": "age"},
+		Dialect: qfl.PostgresDialect{ArrayParameters: true},
+	}
+
+	params, err := builder.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE id = ANY($1) AND name <> ALL($2) AND age = $3\n", builder.Builder.String())
+	assert.Equal(t, []any{[]int{1, 2, 3}, []string{"a", "b"}, 20}, params)
+
+	type wrapped struct{ values any }
+	builder = qfl.SQLBuilder{
+		Filter: filter,
+		Keys:   map[string]string{"id": "id"},
+		Dialect: qfl.PostgresDialect{
+			ArrayParameters: true,
+			WrapArray:       func(values any) any { return wrapped{values} },
+		},
+	}
+
+	params, err = builder.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{wrapped{[]int{1, 2, 3}}}, params)
+}
+
+func TestChunkedLists(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("id", []int{1, 2, 3, 4, 5}, qfl.ComparasionEquals)
+	filter.AddInt("group", []int{6, 7, 8}, qfl.ComparasionNotEquals)
+	filter.AddInt("age", []int{9, 10}, qfl.ComparasionEquals)
+
+	builder := qfl.SQLBuilder{
+		Filter:  filter,
+		Keys:    map[string]string{"id": "id", "group": "group", "age": "age"},
+		Dialect: qfl.MySQLDialect{MaxListSize: 2},
+	}
+
+	params, err := builder.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE (id IN (?,?) OR id IN (?,?) OR id IN (?)) AND "+
+		"(group NOT IN (?,?) AND group NOT IN (?)) AND age IN (?,?)\n", builder.Builder.String())
+	assert.Equal(t, []any{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, params)
+
+	builder = qfl.SQLBuilder{
+		Filter:  filter,
+		Keys:    map[string]string{"id": "id"},
+		Dialect: qfl.SQLiteDialect{MaxListSize: 3},
+	}
+
+	params, err = builder.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE (id IN (?,?,?) OR id IN (?,?))\n", builder.Builder.String())
+	assert.Equal(t, []any{1, 2, 3, 4, 5}, params)
+}
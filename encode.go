@@ -0,0 +1,330 @@
+package qfl
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encode converts the filter back into the query variables read by the
+// parser. Values always have an explicit comparator and their symbols escaped,
+// and the rules of a key are written in the order they were added.
+//
+// `Parse` returns the same rules for them, but it adds the keys in the order
+// they were added to the parser, followed by the ones of the expression. So
+// it returns the same filter only when its keys were added in that order, and
+// a filter with the same `Fingerprint` otherwise.
+//
+// Groups that combine different keys are written on the expression variable,
+// and the sort, fields, page and cursor are written on their variables. It's
+// an error if the filter has anything the parser can't read, including times
+// that `TimeFormat` can't write exactly. Times are written in RFC3339 with
+// fractional seconds by default.
+func (p Parser) Encode(f *Filter) (url.Values, error) {
+	// RFC3339 is parsed with fractional seconds, so they are always written
+	if p.TimeFormat == "" {
+		p.TimeFormat = time.RFC3339Nano
+	}
+
+	vals := url.Values{}
+	for _, key := range f.keys {
+		i := slices.Index(p.keys, key.key)
+		if i == -1 {
+			return nil, fmt.Errorf("key `%s` isn't a key of the parser", key.key)
+		} else if !key.hasType(p.types[i]) {
+			return nil, fmt.Errorf("key `%s` has a different type on the parser", key.key)
+		}
+	}
+
+	// The parser combines the value of each key, and the expression, by AND
+	root := f.expression()
+	nodes := []ruleNode{root}
+	if root.op == logicalAnd {
+		nodes = root.children
+	}
+
+	byKey := make([][]ruleNode, len(f.keys))
+	expression := []string{}
+	for _, node := range nodes {
+		if key, ok := f.singleKey(node); ok {
+			byKey[key] = append(byKey[key], node)
+			continue
+		}
+
+		str, err := p.encodeExpression(f, node, logicalAnd)
+		if err != nil {
+			return nil, err
+		}
+
+		expression = append(expression, str)
+	}
+
+	for i := range byKey {
+		if len(byKey[i]) == 0 {
+			continue
+		}
+
+		// A single group doesn't need parenthesis, as nothing is around it
+		node := ruleNode{op: logicalAnd, children: byKey[i]}
+		if len(byKey[i]) == 1 {
+			node = byKey[i][0]
+		}
+
+		str, err := p.encodeNode(f, node, logicalOr)
+		if err != nil {
+			return nil, err
+		}
+
+		vals.Set(f.keys[i].key, str)
+	}
+
+	if len(expression) != 0 && p.ExpressionKey == "" {
+		return nil, fmt.Errorf("filter combines different keys, but the parser has no `ExpressionKey`")
+	} else if len(expression) != 0 {
+		vals.Set(p.ExpressionKey, strings.Join(expression, " and "))
+	}
+
+	if err := p.encodeExtras(f, vals); err != nil {
+		return nil, err
+	}
+
+	return vals, nil
+}
+
+// encodeExtras writes the sort, fields, page and cursor of the filter.
+func (p Parser) encodeExtras(f *Filter, vals url.Values) error {
+	if len(f.sort) != 0 && p.SortKey == "" {
+		return fmt.Errorf("filter has sort fields, but the parser has no `SortKey`")
+	} else if len(f.sort) != 0 {
+		vals.Set(p.SortKey, sortString(f.sort))
+	}
+
+	if f.fields != nil && p.FieldsKey == "" {
+		return fmt.Errorf("filter has fields, but the parser has no `FieldsKey`")
+	} else if f.fields != nil {
+		vals.Set(p.FieldsKey, strings.Join(f.fields, ","))
+	}
+
+	if f.page != nil && p.Pagination == nil {
+		return fmt.Errorf("filter has a page, but the parser has no `Pagination`")
+	} else if f.page != nil {
+		limitKey, pageKey := p.Pagination.LimitKey, p.Pagination.PageKey
+		if limitKey == "" {
+			limitKey = "limit"
+		}
+		if pageKey == "" {
+			pageKey = "page"
+		}
+
+		vals.Set(limitKey, strconv.FormatUint(f.page.Limit, 10))
+		if f.page.Number != 0 {
+			vals.Set(pageKey, strconv.FormatUint(f.page.Number, 10))
+		}
+	}
+
+	if f.cursor != nil && (p.CursorKey == "" || len(p.CursorSecret) == 0) {
+		return fmt.Errorf("filter has a cursor, but the parser has no `CursorKey` or `CursorSecret`")
+	} else if f.cursor != nil && len(f.cursor.Values) != len(f.sort) {
+		return fmt.Errorf("cursor has %d values, but the filter has %d sort fields", len(f.cursor.Values), len(f.sort))
+	} else if f.cursor != nil {
+		payload := cursorPayload{Sort: sortString(f.sort), Backward: f.cursor.Backward}
+		for i, value := range f.cursor.Values {
+			cv, err := newCursorValue(f.sort[i].Key, reflect.ValueOf(value))
+			if err != nil {
+				return err
+			}
+
+			payload.Values = append(payload.Values, cv)
+		}
+
+		cursor, err := p.signPayload(payload)
+		if err != nil {
+			return err
+		}
+
+		vals.Set(p.CursorKey, cursor)
+	}
+
+	return nil
+}
+
+// singleKey returns the key of all rules of the node, or false if they are
+// from different keys.
+func (f *Filter) singleKey(node ruleNode) (int, bool) {
+	if node.op == logicalLeaf {
+		return node.key, true
+	}
+
+	key := -1
+	for _, child := range node.children {
+		k, ok := f.singleKey(child)
+		if !ok || (key != -1 && k != key) {
+			return 0, false
+		}
+
+		key = k
+	}
+
+	return key, key != -1
+}
+
+// encodeNode writes the rules of the node with the syntax of a key value,
+// grouping them when OR is inside of AND.
+func (p Parser) encodeNode(f *Filter, node ruleNode, parent logicalOp) (string, error) {
+	if node.op == logicalLeaf {
+		return p.encodeRule(f, node, escapeValue)
+	}
+
+	separator := "|"
+	if node.op == logicalOr {
+		separator = "~"
+	}
+
+	children := make([]string, len(node.children))
+	for i := range node.children {
+		str, err := p.encodeNode(f, node.children[i], node.op)
+		if err != nil {
+			return "", err
+		}
+
+		children[i] = str
+	}
+
+	str := strings.Join(children, separator)
+	if node.op == logicalOr && parent == logicalAnd {
+		str = "(" + str + ")"
+	}
+
+	return str, nil
+}
+
+// encodeExpression writes the rules of the node with the syntax of the
+// expression variable, grouping them when OR is inside of AND.
+func (p Parser) encodeExpression(f *Filter, node ruleNode, parent logicalOp) (string, error) {
+	if node.op == logicalLeaf {
+		str, err := p.encodeRule(f, node, escapeExpressionValue)
+		return f.keys[node.key].key + ":" + str, err
+	}
+
+	separator := " and "
+	if node.op == logicalOr {
+		separator = " or "
+	}
+
+	children := make([]string, len(node.children))
+	for i := range node.children {
+		str, err := p.encodeExpression(f, node.children[i], node.op)
+		if err != nil {
+			return "", err
+		}
+
+		children[i] = str
+	}
+
+	str := strings.Join(children, separator)
+	if node.op == logicalOr && parent == logicalAnd {
+		str = "(" + str + ")"
+	}
+
+	return str, nil
+}
+
+// encodeRule writes the comparator of the rule followed by its escaped values.
+func (p Parser) encodeRule(f *Filter, node ruleNode, escape func(string) string) (string, error) {
+	key := f.keys[node.key]
+	rule := key.rules[node.rule]
+	switch rule.Comparasion {
+	case ComparasionIsNull, ComparasionIsNotNull:
+		return rule.Comparasion.identifier(), nil
+	}
+
+	var values []string
+	switch key.Type {
+	case ruleTypeInt:
+		values = formatValues(rule, f.intVals, func(v int) string { return strconv.Itoa(v) })
+	case ruleTypeUint:
+		values = formatValues(rule, f.uintVals, func(v uint) string { return strconv.FormatUint(uint64(v), 10) })
+	case ruleTypeFloat:
+		values = formatValues(rule, f.floatVals, func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) })
+	case ruleTypeString:
+		values = formatValues(rule, f.stringVals, func(v string) string { return v })
+	case ruleTypeTime:
+		values = formatValues(rule, f.timeVals, func(v time.Time) string { return v.Format(p.TimeFormat) })
+		if err := p.checkTimes(key.key, rule, f.timeVals, values); err != nil {
+			return "", err
+		}
+	}
+
+	// The language has no way to write empty values
+	if len(values) == 0 {
+		return "", fmt.Errorf("rule of key `%s` has no values", key.key)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(rule.Comparasion.identifier())
+	sb.WriteRune('!')
+	for i := range values {
+		if values[i] == "" {
+			return "", fmt.Errorf("rule of key `%s` has an empty value", key.key)
+		} else if i != 0 {
+			sb.WriteRune(',')
+		}
+
+		sb.WriteString(escape(values[i]))
+	}
+
+	return sb.String(), nil
+}
+
+// checkTimes returns an error if the formatted values don't parse back into
+// the times of the rule, like when the time format drops some of their parts.
+func (p Parser) checkTimes(key string, rule filterRule, times []time.Time, values []string) error {
+	for i := range rule.indices {
+		parsed, err := time.Parse(p.TimeFormat, values[i])
+		if err != nil || !parsed.Equal(times[rule.indices[i]]) {
+			return fmt.Errorf("time `%s` of key `%s` can't be written with the format `%s`", times[rule.indices[i]].Format(time.RFC3339Nano), key, p.TimeFormat)
+		}
+	}
+
+	return nil
+}
+
+func formatValues[T Primitive](rule filterRule, values []T, format func(T) string) []string {
+	result := make([]string, len(rule.indices))
+	for i := range rule.indices {
+		result[i] = format(values[rule.indices[i]])
+	}
+
+	return result
+}
+
+// escapeValue escapes the symbols of the language in the value.
+func escapeValue(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if strings.IndexByte(`\|,!~()`, value[i]) != -1 {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(value[i])
+	}
+
+	return sb.String()
+}
+
+// escapeExpressionValue escapes the symbols of the language in the value, as
+// well as the whitespace that ends the values of the expression.
+func escapeExpressionValue(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if strings.IndexByte(`\|,!~()`, value[i]) != -1 || isSpace(value[i]) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(value[i])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+package qfl_test
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParser_Encode() {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddString("name")
+
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{20}, qfl.ComparasionMoreThan)
+	filter.AddInt("age", []int{60}, qfl.ComparasionLessThan)
+	filter.AddString("name", []string{"a,b", "c"}, qfl.ComparasionEquals)
+
+	vals, err := parser.Encode(&filter)
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(vals.Get("age"))
+	fmt.Println(vals.Get("name"))
+	fmt.Println(vals.Encode())
+	// Output:
+	// gt!20|lt!60
+	// eq!a\,b,c
+	// age=gt%2120%7Clt%2160&name=eq%21a%5C%2Cb%2Cc
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	parser := qfl.Parser{
+		ExpressionKey: "q",
+		SortKey:       "sort",
+		FieldsKey:     "fields",
+		CursorKey:     "cursor",
+		CursorSecret:  []byte("secret"),
+		Pagination:    &qfl.Pagination{DefaultSize: 10, MaxSize: 100},
+	}
+	parser.AddInt("age")
+	parser.AddUint("id")
+	parser.AddFloat("score")
+	parser.AddString("name")
+	parser.AddTime("created")
+	parser.AddSort("id", "age")
+	parser.AddField("id", "name")
+
+	cases := []map[string]string{
+		{"age": "20"},
+		{"age": "gt!20|lt!60", "id": "eq!1,2,3"},
+		{"age": "(lt!18~gt!60)|ne!99", "score": "bt!0.5,1e+10"},
+		{"name": `eq!a\,b,c\|d,e\!f,g\\h,i\~j,k\(l\)`},
+		{"name": "is!null~lk!jo%"},
+		{"created": "ge!2024-01-02T03:04:05Z"},
+		{"age": "gt!20", "q": `name:eq!a\ b or (id:eq!1 and age:lt!5)`},
+		{"q": "name:eq!à or id:gt!10"},
+		{"sort": "-id,age", "fields": "name,id", "limit": "20", "page": "2"},
+	}
+
+	for _, c := range cases {
+		fm, err := parser.Parse(c)
+		if !assert.NoError(t, err, c) {
+			continue
+		}
+
+		vals, err := parser.Encode(fm)
+		if !assert.NoError(t, err, c) {
+			continue
+		}
+
+		u := &url.URL{RawQuery: vals.Encode()}
+		got, err := parser.ParseURL(u)
+		if assert.NoError(t, err, c) {
+			assert.Equal(t, fm, got, c)
+		}
+	}
+}
+
+func TestEncodeCursor(t *testing.T) {
+	parser := qfl.Parser{SortKey: "sort", CursorKey: "cursor", CursorSecret: []byte("secret")}
+	parser.AddSort("id")
+
+	fm, err := parser.Parse(map[string]string{"sort": "id"})
+	assert.NoError(t, err)
+
+	cursor, err := parser.NextCursor(fm, map[string]any{"id": 10})
+	assert.NoError(t, err)
+
+	fm, err = parser.Parse(map[string]string{"sort": "id", "cursor": cursor})
+	assert.NoError(t, err)
+
+	vals, err := parser.Encode(fm)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, vals.Get("cursor"))
+}
+
+func TestEncodeErrors(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddString("name")
+
+	cases := []func(f *qfl.Filter){
+		func(f *qfl.Filter) { f.AddInt("unknown", []int{1}, qfl.ComparasionEquals) },
+		func(f *qfl.Filter) { f.AddTime("age", []time.Time{time.Now()}, qfl.ComparasionEquals) },
+		func(f *qfl.Filter) { f.AddString("name", []string{""}, qfl.ComparasionEquals) },
+		func(f *qfl.Filter) { f.AddInt("age", []int{}, qfl.ComparasionEquals) },
+		func(f *qfl.Filter) { f.AddSort(qfl.SortField{Key: "age"}) },
+		func(f *qfl.Filter) { f.SetFields("age") },
+		func(f *qfl.Filter) { f.SetPage(&qfl.Page{Limit: 10}) },
+	}
+
+	for i, c := range cases {
+		filter := qfl.Filter{}
+		c(&filter)
+
+		_, err := parser.Encode(&filter)
+		assert.Error(t, err, i)
+	}
+
+	// Rules of different keys combined by OR need the expression variable
+	exprParser := parser
+	exprParser.ExpressionKey = "q"
+
+	fm, err := exprParser.Parse(map[string]string{"q": "age:1 or name:a"})
+	assert.NoError(t, err)
+
+	_, err = parser.Encode(fm)
+	assert.Error(t, err)
+}
+
+func TestEncodeKeyOrder(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddInt("id")
+	parser.AddString("name")
+
+	// Keys added in a different order than the parser
+	filter := qfl.Filter{}
+	filter.AddString("name", []string{"b", "a"}, qfl.ComparasionEquals)
+	filter.AddInt("id", []int{7}, qfl.ComparasionMoreThan)
+	filter.AddInt("age", []int{20}, qfl.ComparasionLessThan)
+	filter.AddInt("id", []int{9}, qfl.ComparasionLessThan)
+
+	vals, err := parser.Encode(&filter)
+	assert.NoError(t, err)
+
+	got, err := parser.ParseURL(&url.URL{RawQuery: vals.Encode()})
+	assert.NoError(t, err)
+
+	// The parsed filter has its keys in the order of the parser
+	ordered := qfl.Filter{}
+	ordered.AddInt("age", []int{20}, qfl.ComparasionLessThan)
+	ordered.AddInt("id", []int{7}, qfl.ComparasionMoreThan)
+	ordered.AddInt("id", []int{9}, qfl.ComparasionLessThan)
+	ordered.AddString("name", []string{"b", "a"}, qfl.ComparasionEquals)
+
+	assert.Equal(t, &ordered, got)
+	assert.Equal(t, filter.Fingerprint(), got.Fingerprint())
+}
+
+func TestEncodeTimes(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddTime("created")
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	filter := qfl.Filter{}
+	filter.AddTime("created", []time.Time{created}, qfl.ComparasionMoreThan)
+
+	vals, err := parser.Encode(&filter)
+	assert.NoError(t, err)
+	assert.Equal(t, "gt!2024-01-02T03:04:05.123456789Z", vals.Get("created"))
+
+	got, err := parser.ParseURL(&url.URL{RawQuery: vals.Encode()})
+	assert.NoError(t, err)
+	assert.Equal(t, &filter, got)
+	assert.Equal(t, filter.Fingerprint(), got.Fingerprint())
+
+	// Formats that drop part of the time can't be parsed back into it
+	parser.TimeFormat = time.DateOnly
+	_, err = parser.Encode(&filter)
+	assert.EqualError(t, err, "time `2024-01-02T03:04:05.123456789Z` of key `created` can't be written with the format `2006-01-02`")
+
+	date := qfl.Filter{}
+	date.AddTime("created", []time.Time{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}, qfl.ComparasionMoreThan)
+	vals, err = parser.Encode(&date)
+	assert.NoError(t, err)
+	assert.Equal(t, "gt!2024-01-02", vals.Get("created"))
+}
@@ -0,0 +1,167 @@
+package qfl
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseErrorKind enumerates the reasons why the parser rejected a value.
+type ParseErrorKind uint8
+
+const (
+	ParseErrorUnexpectedToken ParseErrorKind = iota + 1
+	ParseErrorInvalidComparator
+	ParseErrorInvalidValue
+	ParseErrorInvalidRange
+	ParseErrorUnknownKey
+	ParseErrorComparatorNotAllowed
+	ParseErrorTooManyValues
+	ParseErrorTooManyRules
+	ParseErrorValueTooLong
+	ParseErrorInvalidCursor
+	ParseErrorOutOfRange
+)
+
+// String returns a stable code for the kind, suitable to be sent to clients.
+func (k ParseErrorKind) String() string {
+	switch k {
+	case ParseErrorUnexpectedToken:
+		return "unexpected_token"
+	case ParseErrorInvalidComparator:
+		return "invalid_comparator"
+	case ParseErrorInvalidValue:
+		return "invalid_value"
+	case ParseErrorInvalidRange:
+		return "invalid_range"
+	case ParseErrorUnknownKey:
+		return "unknown_key"
+	case ParseErrorComparatorNotAllowed:
+		return "comparator_not_allowed"
+	case ParseErrorTooManyValues:
+		return "too_many_values"
+	case ParseErrorTooManyRules:
+		return "too_many_rules"
+	case ParseErrorValueTooLong:
+		return "value_too_long"
+	case ParseErrorInvalidCursor:
+		return "invalid_cursor"
+	case ParseErrorOutOfRange:
+		return "out_of_range"
+	default:
+		return "invalid"
+	}
+}
+
+// ParseError describes why the value of a query variable couldn't be parsed.
+// The parser always returns it as a pointer, so it can be retrieved with
+// `errors.As`.
+type ParseError struct {
+	Key      string         // query variable that contains the error
+	Offset   int            // byte offset of the token in the raw value
+	Token    string         // offending token, empty at the end of the value
+	Kind     ParseErrorKind // reason of the error
+	Expected []string       // tokens, or type and format, that were expected instead
+	Limit    int            // limit of the key option that was exceeded
+}
+
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString("key `")
+	sb.WriteString(e.Key)
+	sb.WriteString("` at offset ")
+	sb.WriteString(strconv.Itoa(e.Offset))
+	sb.WriteString(": ")
+
+	switch e.Kind {
+	case ParseErrorUnexpectedToken:
+		sb.WriteString("expected ")
+		writeExpected(&sb, e.Expected)
+		if e.Token == "" {
+			sb.WriteString(", got end of value")
+		} else {
+			sb.WriteString(", got `")
+			sb.WriteString(e.Token)
+			sb.WriteRune('`')
+		}
+	case ParseErrorInvalidComparator:
+		sb.WriteString("comparator `")
+		sb.WriteString(e.Token)
+		sb.WriteString("` is invalid")
+	case ParseErrorInvalidValue:
+		sb.WriteString("value `")
+		sb.WriteString(e.Token)
+		sb.WriteString("` is not a valid ")
+		if len(e.Expected) > 0 {
+			sb.WriteString(e.Expected[0])
+		}
+
+		// Times also have the format they should follow
+		if len(e.Expected) > 1 {
+			sb.WriteString(" formatted as `")
+			sb.WriteString(e.Expected[1])
+			sb.WriteRune('`')
+		}
+	case ParseErrorInvalidRange:
+		sb.WriteString("lower bound `")
+		sb.WriteString(e.Token)
+		sb.WriteString("` is greater than the upper bound")
+	case ParseErrorUnknownKey:
+		sb.WriteString("key `")
+		sb.WriteString(e.Token)
+		sb.WriteString("` is not supported")
+	case ParseErrorComparatorNotAllowed:
+		sb.WriteString("comparator `")
+		sb.WriteString(e.Token)
+		sb.WriteString("` is not allowed, expected ")
+		writeExpected(&sb, e.Expected)
+	case ParseErrorTooManyValues:
+		sb.WriteString("too many values, the limit is ")
+		sb.WriteString(strconv.Itoa(e.Limit))
+	case ParseErrorTooManyRules:
+		sb.WriteString("too many rules, the limit is ")
+		sb.WriteString(strconv.Itoa(e.Limit))
+	case ParseErrorValueTooLong:
+		sb.WriteString("value is longer than ")
+		sb.WriteString(strconv.Itoa(e.Limit))
+		sb.WriteString(" characters")
+	case ParseErrorInvalidCursor:
+		sb.WriteString("cursor is invalid or doesn't match the sort")
+	case ParseErrorOutOfRange:
+		sb.WriteString("value `")
+		sb.WriteString(e.Token)
+		sb.WriteString("` is out of range, the limit is ")
+		sb.WriteString(strconv.Itoa(e.Limit))
+	default:
+		sb.WriteString("invalid token `")
+		sb.WriteString(e.Token)
+		sb.WriteRune('`')
+	}
+
+	return sb.String()
+}
+
+// writeExpected writes the list of expected tokens separated by commas, except
+// the last one that is separated by `or`.
+func writeExpected(sb *strings.Builder, expected []string) {
+	for i := range expected {
+		if i == len(expected)-1 && i != 0 {
+			sb.WriteString(" or ")
+		} else if i != 0 {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteRune('`')
+		sb.WriteString(expected[i])
+		sb.WriteRune('`')
+	}
+}
+
+func newParseError(kind ParseErrorKind, tok token, expected ...string) *ParseError {
+	return &ParseError{
+		Offset:   tok.Offset,
+		Token:    tok.Value,
+		Kind:     kind,
+		Expected: expected,
+	}
+}
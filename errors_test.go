@@ -0,0 +1,125 @@
+package qfl_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParseError() {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+
+	_, err := parser.Parse(map[string]string{"age": "gt!20|lt!sixty"})
+
+	var pe *qfl.ParseError
+	if errors.As(err, &pe) {
+		fmt.Println(pe.Key, pe.Offset, pe.Token, pe.Kind)
+		fmt.Println(pe)
+	}
+	// Output:
+	// age 9 sixty invalid_value
+	// key `age` at offset 9: value `sixty` is not a valid int
+}
+
+func TestParseErrorDetails(t *testing.T) {
+	parser := qfl.Parser{ExpressionKey: "filter"}
+	parser.AddInt("age")
+	parser.AddString("name")
+
+	cases := []struct {
+		key      string
+		value    string
+		offset   int
+		token    string
+		kind     qfl.ParseErrorKind
+		expected []string
+	}{
+		{"age", "gt!20|lt", 6, "lt", qfl.ParseErrorUnexpectedToken, []string{"comparator", "("}},
+		{"age", "gt!20|xx!20", 6, "xx", qfl.ParseErrorInvalidComparator, nil},
+		{"age", "gt!", 3, "", qfl.ParseErrorUnexpectedToken, []string{"value"}},
+		{"age", "(gt!1~lt!5", 10, "", qfl.ParseErrorUnexpectedToken, []string{"|", "~", ")"}},
+		{"age", "gt!1,2", 4, ",", qfl.ParseErrorUnexpectedToken, []string{"|", "~", "end"}},
+		{"age", "bt!1", 4, "", qfl.ParseErrorUnexpectedToken, []string{","}},
+		{"age", "bt!5,1", 3, "5", qfl.ParseErrorInvalidRange, nil},
+		{"age", "is!nil", 3, "nil", qfl.ParseErrorUnexpectedToken, []string{"null", "notnull"}},
+		{"age", "twenty", 0, "twenty", qfl.ParseErrorInvalidValue, []string{"int"}},
+		{"filter", "name:John or salary:gt!20", 13, "salary", qfl.ParseErrorUnknownKey, nil},
+		{"filter", "name:John and age:gt!x", 21, "x", qfl.ParseErrorInvalidValue, []string{"int"}},
+		{"filter", "name:John age:1", 10, "age:1", qfl.ParseErrorUnexpectedToken, []string{"and", "or", "end"}},
+	}
+
+	for _, c := range cases {
+		_, err := parser.Parse(map[string]string{c.key: c.value})
+
+		var pe *qfl.ParseError
+		if assert.ErrorAs(t, err, &pe, c.value) {
+			assert.Equal(t, c.key, pe.Key, c.value)
+			assert.Equal(t, c.offset, pe.Offset, c.value)
+			assert.Equal(t, c.token, pe.Token, c.value)
+			assert.Equal(t, c.kind, pe.Kind, c.value)
+			assert.Equal(t, c.expected, pe.Expected, c.value)
+		}
+	}
+}
+
+func TestParserCollectErrors(t *testing.T) {
+	parser := qfl.Parser{CollectErrors: true, ExpressionKey: "filter"}
+	parser.AddInt("age")
+	parser.AddString("name")
+	parser.AddFloat("salary")
+
+	fm, err := parser.Parse(map[string]string{
+		"age":    "gt!20|lt!sixty",
+		"name":   "lk!Jo%",
+		"salary": "xx!1000",
+		"filter": "age:gt!1 or",
+	})
+
+	if assert.Error(t, err) {
+		joined, ok := err.(interface{ Unwrap() []error })
+		if assert.True(t, ok) {
+			keys := []string{}
+			for _, err := range joined.Unwrap() {
+				var pe *qfl.ParseError
+				if assert.ErrorAs(t, err, &pe) {
+					keys = append(keys, pe.Key)
+				}
+			}
+
+			assert.Equal(t, []string{"age", "salary", "filter"}, keys)
+		}
+
+		var pe *qfl.ParseError
+		assert.ErrorAs(t, err, &pe)
+	}
+
+	// Only the valid keys are kept, without rules from the invalid ones
+	if assert.NotNil(t, fm) {
+		assert.Nil(t, fm.GetInt("age"))
+		assert.Nil(t, fm.GetFloat("salary"))
+		assert.Equal(t, 1, len(fm.GetString("name")))
+	}
+
+	fm, err = parser.Parse(map[string]string{"name": "John"})
+	assert.NoError(t, err)
+	assert.NotNil(t, fm)
+}
+
+func TestParserStopsOnFirstError(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddString("name")
+
+	fm, err := parser.Parse(map[string]string{"age": "gt!x", "name": "John"})
+	assert.Nil(t, fm)
+
+	var pe *qfl.ParseError
+	if assert.ErrorAs(t, err, &pe) {
+		assert.Equal(t, "age", pe.Key)
+	}
+}
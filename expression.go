@@ -0,0 +1,245 @@
+package qfl
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// parseExpression parses an expression that combines the rules of different
+// keys, following the grammar below where `and` has precedence over `or`:
+//
+//	or     = and { "or" and }
+//	and    = factor { "and" factor }
+//	factor = "(" or ")" | key ":" value
+//
+// The value of each key is written in QFL, but whitespace and unbalanced
+// parenthesis must be escaped.
+//
+// The offset of the errors are relative to the start of the expression,
+// including the ones for values.
+func (p Parser) parseExpression(str string) (ruleExpr, *ParseError) {
+	es := exprScanner{str: str}
+	if err := es.scan(); err != nil {
+		return ruleExpr{}, err
+	}
+
+	expr, err := p.parseExprOr(&es)
+	if err != nil {
+		return expr, err
+	}
+
+	if tok := es.next(); tok.Type != exprEnd {
+		return expr, tok.error(ParseErrorUnexpectedToken, "and", "or", "end")
+	}
+
+	return expr, nil
+}
+
+func (p Parser) parseExprOr(es *exprScanner) (ruleExpr, *ParseError) {
+	return p.parseExprList(es, logicalOr, p.parseExprAnd)
+}
+
+func (p Parser) parseExprAnd(es *exprScanner) (ruleExpr, *ParseError) {
+	return p.parseExprList(es, logicalAnd, p.parseExprFactor)
+}
+
+// parseExprList parses a list of operands separated by the keyword of the
+// operator.
+func (p Parser) parseExprList(es *exprScanner, op logicalOp, operand func(*exprScanner) (ruleExpr, *ParseError)) (ruleExpr, *ParseError) {
+	first, err := operand(es)
+	if err != nil {
+		return first, err
+	}
+
+	keyword := exprAnd
+	if op == logicalOr {
+		keyword = exprOr
+	}
+
+	expr := ruleExpr{op: op, children: []ruleExpr{first}}
+	for es.peek().Type == keyword {
+		es.next()
+
+		child, err := operand(es)
+		if err != nil {
+			return expr, err
+		}
+
+		expr.children = append(expr.children, child)
+	}
+
+	if len(expr.children) == 1 {
+		return first, nil
+	}
+
+	return expr, nil
+}
+
+func (p Parser) parseExprFactor(es *exprScanner) (ruleExpr, *ParseError) {
+	tok := es.next()
+	switch tok.Type {
+	case exprOpen:
+		expr, err := p.parseExprOr(es)
+		if err != nil {
+			return expr, err
+		}
+
+		if tok := es.next(); tok.Type != exprClose {
+			return expr, tok.error(ParseErrorUnexpectedToken, "and", "or", ")")
+		}
+
+		return expr, nil
+	case exprTerm:
+		key := -1
+		for i := range p.keys {
+			if p.keys[i] == tok.Key {
+				key = i
+				break
+			}
+		}
+
+		if key == -1 {
+			return ruleExpr{}, &ParseError{Offset: tok.Offset, Token: tok.Key, Kind: ParseErrorUnknownKey}
+		}
+
+		// Make the offset of the tokens relative to the whole expression
+		tokens := p.tokenize(tok.Value)
+		for i := range tokens {
+			tokens[i].Offset += tok.ValueOffset
+		}
+
+		expr, err := parseTokens(tokens)
+		if err != nil {
+			return expr, err
+		}
+
+		expr.setKey(key)
+		return expr, nil
+	}
+
+	return ruleExpr{}, tok.error(ParseErrorUnexpectedToken, "key:value", "(")
+}
+
+type exprScanner struct {
+	str    string
+	tokens []exprToken
+	pos    int
+}
+
+type exprToken struct {
+	Type        exprTokenType
+	Key         string
+	Value       string
+	Offset      int
+	ValueOffset int
+}
+
+func (tok exprToken) error(kind ParseErrorKind, expected ...string) *ParseError {
+	token := tok.Value
+	if tok.Type == exprTerm {
+		token = tok.Key + ":" + tok.Value
+	}
+
+	return &ParseError{Offset: tok.Offset, Token: token, Kind: kind, Expected: expected}
+}
+
+func (es *exprScanner) peek() exprToken {
+	return es.tokens[es.pos]
+}
+
+func (es *exprScanner) next() exprToken {
+	tok := es.tokens[es.pos]
+	if tok.Type != exprEnd {
+		es.pos += 1
+	}
+
+	return tok
+}
+
+// scan splits the expression into parenthesis, keywords and `key:value` terms.
+// The value of a term goes until an unescaped whitespace or a parenthesis that
+// closes a group outside of it.
+func (es *exprScanner) scan() *ParseError {
+	str := es.str
+	for i := 0; i < len(str); {
+		switch c := rune(str[i]); {
+		case isSpace(str[i]):
+			i++
+		case c == '(':
+			es.tokens = append(es.tokens, exprToken{Type: exprOpen, Value: "(", Offset: i})
+			i++
+		case c == ')':
+			es.tokens = append(es.tokens, exprToken{Type: exprClose, Value: ")", Offset: i})
+			i++
+		default:
+			start := i
+			for i < len(str) && !strings.ContainsRune(" \t\n\r():", rune(str[i])) {
+				i++
+			}
+
+			word := str[start:i]
+			if i == len(str) || str[i] != ':' {
+				switch strings.ToLower(word) {
+				case "and":
+					es.tokens = append(es.tokens, exprToken{Type: exprAnd, Value: word, Offset: start})
+				case "or":
+					es.tokens = append(es.tokens, exprToken{Type: exprOr, Value: word, Offset: start})
+				default:
+					return &ParseError{Offset: start, Token: word, Kind: ParseErrorUnexpectedToken, Expected: []string{"key:value"}}
+				}
+				continue
+			}
+
+			i++
+			valueStart := i
+			depth := 0
+			escaped := false
+		value:
+			for ; i < len(str); i++ {
+				switch {
+				case escaped:
+					escaped = false
+				case str[i] == '\\':
+					escaped = true
+				case str[i] == '(':
+					depth++
+				case str[i] == ')' && depth == 0:
+					break value
+				case str[i] == ')':
+					depth--
+				case isSpace(str[i]):
+					break value
+				}
+			}
+
+			es.tokens = append(es.tokens, exprToken{
+				Type:        exprTerm,
+				Key:         word,
+				Value:       str[valueStart:i],
+				Offset:      start,
+				ValueOffset: valueStart,
+			})
+		}
+	}
+
+	es.tokens = append(es.tokens, exprToken{Type: exprEnd, Value: "", Offset: len(str)})
+	return nil
+}
+
+// isSpace tells if the byte is an ASCII whitespace, bytes of multi-byte
+// characters are never whitespace.
+func isSpace(b byte) bool {
+	return b < utf8.RuneSelf && unicode.IsSpace(rune(b))
+}
+
+type exprTokenType uint
+
+const (
+	exprOpen exprTokenType = iota + 1
+	exprClose
+	exprAnd
+	exprOr
+	exprTerm
+	exprEnd
+)
@@ -0,0 +1,115 @@
+package qfl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParser_expression() {
+	parser := qfl.Parser{ExpressionKey: "filter"}
+	parser.AddString("role")
+	parser.AddInt("age")
+	parser.AddString("status")
+
+	filter, err := parser.Parse(map[string]string{
+		"status": "active",
+		"filter": "role:eq!admin or (age:gt!30 and role:lk!%Tester)",
+	})
+	if err != nil {
+		// Treat error...
+	}
+
+	builder := qfl.SQLBuilder{
+		Filter: *filter,
+		Keys: map[string]string{
+			"role":   "role",
+			"age":    "age",
+			"status": "status",
+		},
+		PlaceholderFormat: qfl.SQLPlaceholderDollarSign,
+	}
+
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// WHERE status = $1 AND (role = $2 OR (age > $3 AND role LIKE $4))
+	//
+	// [active admin 30 %Tester]
+}
+
+func TestParserExpression(t *testing.T) {
+	parser := qfl.Parser{ExpressionKey: "filter"}
+	parser.AddString("name")
+	parser.AddInt("age")
+
+	fm, err := parser.Parse(map[string]string{"filter": `name:eq!John\ Doe OR age:(lt!18~gt!65) AND name:lk!J%`})
+	assert.NoError(t, err)
+
+	names := fm.GetString("name")
+	if assert.Equal(t, 2, len(names)) {
+		assert.Equal(t, "John Doe", names[0].Values[0])
+		assert.Equal(t, "J%", names[1].Values[0])
+	}
+	assert.Equal(t, 2, len(fm.GetInt("age")))
+
+	records := []struct {
+		record map[string]any
+		match  bool
+	}{
+		{map[string]any{"name": "John Doe", "age": 30}, true},
+		{map[string]any{"name": "Jane", "age": 10}, true},
+		{map[string]any{"name": "Jane", "age": 30}, false},
+		{map[string]any{"name": "Mary", "age": 10}, false},
+	}
+
+	for _, r := range records {
+		ok, err := fm.Match(r.record)
+		assert.NoError(t, err)
+		assert.Equal(t, r.match, ok, "%v", r.record)
+	}
+}
+
+func TestParserExpressionUnicode(t *testing.T) {
+	parser := qfl.Parser{ExpressionKey: "filter"}
+	parser.AddString("name")
+	parser.AddInt("age")
+
+	// Bytes of multi-byte characters (like 0x85 and 0xA0) aren't whitespace
+	fm, err := parser.Parse(map[string]string{"filter": "name:eq!à or name:Ġ\u0085\u00a0 or age:1"})
+	assert.NoError(t, err)
+
+	names := fm.GetString("name")
+	if assert.Equal(t, 2, len(names)) {
+		assert.Equal(t, "à", names[0].Values[0])
+		assert.Equal(t, "Ġ\u0085\u00a0", names[1].Values[0])
+	}
+}
+
+func TestParserInvalidExpression(t *testing.T) {
+	parser := qfl.Parser{ExpressionKey: "filter"}
+	parser.AddString("name")
+	parser.AddInt("age")
+
+	for _, value := range []string{
+		"name:John or",
+		"(name:John",
+		"name:John age:20",
+		"salary:gt!20",
+		"age:gt!twenty",
+		"age:(lt!18~gt!65",
+		"and name:John",
+		"name",
+	} {
+		_, err := parser.Parse(map[string]string{"filter": value})
+		assert.Error(t, err, value)
+	}
+}
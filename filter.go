@@ -1,6 +1,7 @@
 package qfl
 
 import (
+	"slices"
 	"time"
 )
 
@@ -20,6 +21,12 @@ const (
 	ComparasionLessOrEqual
 	ComparasionMoreOrEqual
 	ComparasionLike
+	ComparasionNotEquals
+	ComparasionNotLike
+	ComparasionIsNull
+	ComparasionIsNotNull
+	ComparasionBetween
+	ComparasionBetweenExclusive
 )
 
 func (c ComparasionType) String() string {
@@ -36,13 +43,37 @@ func (c ComparasionType) String() string {
 		return "MoreOrEqual"
 	case ComparasionMoreThan:
 		return "MoreThan"
+	case ComparasionNotEquals:
+		return "NotEquals"
+	case ComparasionNotLike:
+		return "NotLike"
+	case ComparasionIsNull:
+		return "IsNull"
+	case ComparasionIsNotNull:
+		return "IsNotNull"
+	case ComparasionBetween:
+		return "Between"
+	case ComparasionBetweenExclusive:
+		return "BetweenExclusive"
 	default:
 		return "Invalid"
 	}
 }
 
+// isList tells if the comparator accepts more than one value.
+func (c ComparasionType) isList() bool {
+	return c == ComparasionEquals || c == ComparasionNotEquals || c.isRange()
+}
+
+func (c ComparasionType) isRange() bool {
+	return c == ComparasionBetween || c == ComparasionBetweenExclusive
+}
+
 // FilterRule represents
-// Note that `ComparasionEquals` is the only one that can have more than one value.
+// Note that `ComparasionEquals` and `ComparasionNotEquals` are the only ones
+// that can have more than one value, while `ComparasionIsNull` and
+// `ComparasionIsNotNull` doesn't have any. `ComparasionBetween` and
+// `ComparasionBetweenExclusive` have exactly two: the lower and upper bounds.
 type FilterRule[T Primitive] struct {
 	Comparasion ComparasionType
 	Values      []T
@@ -52,9 +83,32 @@ type FilterRule[T Primitive] struct {
 // only supports some primitive data types. All get and set functions should be
 // the exactly same except for the type it's manipulating, this is on purporse
 // to ensure type safety.
+//
+// Rules are combined with AND by default, unless they were grouped with OR by
+// the parser. The get functions return all rules of a key regardless of how
+// they are combined.
+//
+// Filters can be copied by value, adding rules to a copy doesn't change the
+// original.
 type Filter struct {
 	keys []filterKey
 
+	// Boolean expression that combines the rules, when it's nil all rules are
+	// combined with AND.
+	expr *ruleNode
+
+	// Fields to sort the results by, in order of precedence
+	sort []SortField
+
+	// Position of the row to paginate from, nil when starting from the first
+	cursor *Cursor
+
+	// Page of the results to fetch, nil when fetching all of them
+	page *Page
+
+	// Keys of the fields to select, nil when selecting all of them
+	fields []string
+
 	intVals    []int
 	uintVals   []uint
 	floatVals  []float64
@@ -64,7 +118,7 @@ type Filter struct {
 
 func (f *Filter) GetInt(key string) []FilterRule[int] {
 	for i := range f.keys {
-		if f.keys[i].key == key && f.keys[i].Type == ruleTypeInt {
+		if f.keys[i].key == key && f.keys[i].hasType(ruleTypeInt) {
 			return getGeneric(f.keys[i], f.intVals)
 		}
 	}
@@ -74,7 +128,7 @@ func (f *Filter) GetInt(key string) []FilterRule[int] {
 
 func (f *Filter) GetUint(key string) []FilterRule[uint] {
 	for i := range f.keys {
-		if f.keys[i].key == key && f.keys[i].Type == ruleTypeUint {
+		if f.keys[i].key == key && f.keys[i].hasType(ruleTypeUint) {
 			return getGeneric(f.keys[i], f.uintVals)
 		}
 	}
@@ -84,7 +138,7 @@ func (f *Filter) GetUint(key string) []FilterRule[uint] {
 
 func (f *Filter) GetFloat(key string) []FilterRule[float64] {
 	for i := range f.keys {
-		if f.keys[i].key == key && f.keys[i].Type == ruleTypeFloat {
+		if f.keys[i].key == key && f.keys[i].hasType(ruleTypeFloat) {
 			return getGeneric(f.keys[i], f.floatVals)
 		}
 	}
@@ -94,7 +148,7 @@ func (f *Filter) GetFloat(key string) []FilterRule[float64] {
 
 func (f *Filter) GetString(key string) []FilterRule[string] {
 	for i := range f.keys {
-		if f.keys[i].key == key && f.keys[i].Type == ruleTypeString {
+		if f.keys[i].key == key && f.keys[i].hasType(ruleTypeString) {
 			return getGeneric(f.keys[i], f.stringVals)
 		}
 	}
@@ -104,7 +158,7 @@ func (f *Filter) GetString(key string) []FilterRule[string] {
 
 func (f *Filter) GetTime(key string) []FilterRule[time.Time] {
 	for i := range f.keys {
-		if f.keys[i].key == key && f.keys[i].Type == ruleTypeTime {
+		if f.keys[i].key == key && f.keys[i].hasType(ruleTypeTime) {
 			return getGeneric(f.keys[i], f.timeVals)
 		}
 	}
@@ -135,7 +189,7 @@ func getGeneric[T Primitive](key filterKey, vals []T) []FilterRule[T] {
 
 func (f *Filter) AddInt(key string, values []int, comparasion ComparasionType) {
 	start := len(f.intVals)
-	f.intVals = append(f.intVals, values...)
+	f.intVals = append(slices.Clip(f.intVals), values...)
 	end := len(f.intVals)
 
 	indices := generateSequence(start, end)
@@ -144,7 +198,7 @@ func (f *Filter) AddInt(key string, values []int, comparasion ComparasionType) {
 
 func (f *Filter) AddUint(key string, values []uint, comparasion ComparasionType) {
 	start := len(f.uintVals)
-	f.uintVals = append(f.uintVals, values...)
+	f.uintVals = append(slices.Clip(f.uintVals), values...)
 	end := len(f.uintVals)
 
 	indices := generateSequence(start, end)
@@ -153,7 +207,7 @@ func (f *Filter) AddUint(key string, values []uint, comparasion ComparasionType)
 
 func (f *Filter) AddFloat(key string, values []float64, comparasion ComparasionType) {
 	start := len(f.floatVals)
-	f.floatVals = append(f.floatVals, values...)
+	f.floatVals = append(slices.Clip(f.floatVals), values...)
 	end := len(f.floatVals)
 
 	indices := generateSequence(start, end)
@@ -162,7 +216,7 @@ func (f *Filter) AddFloat(key string, values []float64, comparasion ComparasionT
 
 func (f *Filter) AddString(key string, values []string, comparasion ComparasionType) {
 	start := len(f.stringVals)
-	f.stringVals = append(f.stringVals, values...)
+	f.stringVals = append(slices.Clip(f.stringVals), values...)
 	end := len(f.stringVals)
 
 	indices := generateSequence(start, end)
@@ -171,22 +225,37 @@ func (f *Filter) AddString(key string, values []string, comparasion ComparasionT
 
 func (f *Filter) AddTime(key string, values []time.Time, comparasion ComparasionType) {
 	start := len(f.timeVals)
-	f.timeVals = append(f.timeVals, values...)
+	f.timeVals = append(slices.Clip(f.timeVals), values...)
 	end := len(f.timeVals)
 
 	indices := generateSequence(start, end)
 	f.appendRule(key, indices, comparasion, ruleTypeTime)
 }
 
+// AddNull adds a rule that checks the presence of the key, so the comparasion
+// must be either `ComparasionIsNull` or `ComparasionIsNotNull`. Since it
+// doesn't have values, it can be added on a key of any type.
+func (f *Filter) AddNull(key string, comparasion ComparasionType) {
+	f.appendRule(key, nil, comparasion, ruleTypeNone)
+}
+
 func (f *Filter) appendRule(key string, indices []int, comparasion ComparasionType, ruleType ruleType) {
 	rule := filterRule{
 		Comparasion: comparasion,
 		indices:     indices,
 	}
 
+	// Slices are never changed in place, as they may be shared with copies
+	// of the filter
 	for i := range f.keys {
 		if f.keys[i].key == key {
-			f.keys[i].rules = append(f.keys[i].rules, rule)
+			f.keys = slices.Clone(f.keys)
+			if f.keys[i].Type == ruleTypeNone {
+				f.keys[i].Type = ruleType
+			}
+
+			f.keys[i].rules = append(slices.Clip(f.keys[i].rules), rule)
+			f.appendExpr(ruleNode{key: i, rule: len(f.keys[i].rules) - 1})
 			return
 		}
 	}
@@ -196,7 +265,65 @@ func (f *Filter) appendRule(key string, indices []int, comparasion ComparasionTy
 		Type:  ruleType,
 		rules: []filterRule{rule},
 	}
-	f.keys = append(f.keys, k)
+	f.keys = append(slices.Clip(f.keys), k)
+	f.appendExpr(ruleNode{key: len(f.keys) - 1})
+}
+
+// appendExpr combines the node with the expression of the filter using AND.
+func (f *Filter) appendExpr(node ruleNode) {
+	if f.expr == nil {
+		return
+	}
+
+	if f.expr.op == logicalAnd {
+		f.expr = &ruleNode{op: logicalAnd, children: append(slices.Clip(f.expr.children), node)}
+	} else {
+		f.expr = &ruleNode{op: logicalAnd, children: []ruleNode{*f.expr, node}}
+	}
+}
+
+// expression returns the boolean expression of the filter, building the
+// implicit AND of all rules when there isn't one.
+func (f *Filter) expression() ruleNode {
+	if f.expr != nil {
+		return *f.expr
+	}
+
+	root := ruleNode{op: logicalAnd}
+	for i := range f.keys {
+		for j := range f.keys[i].rules {
+			root.children = append(root.children, ruleNode{key: i, rule: j})
+		}
+	}
+
+	return root
+}
+
+// addRule adds the values into the filter using the typed functions and
+// returns the leaf pointing to the rule.
+func (f *Filter) addRule(key string, ruleType ruleType, values any, comparasion ComparasionType) ruleNode {
+	switch {
+	case comparasion == ComparasionIsNull || comparasion == ComparasionIsNotNull:
+		f.appendRule(key, nil, comparasion, ruleType)
+	case ruleType == ruleTypeInt:
+		f.AddInt(key, values.([]int), comparasion)
+	case ruleType == ruleTypeUint:
+		f.AddUint(key, values.([]uint), comparasion)
+	case ruleType == ruleTypeFloat:
+		f.AddFloat(key, values.([]float64), comparasion)
+	case ruleType == ruleTypeString:
+		f.AddString(key, values.([]string), comparasion)
+	case ruleType == ruleTypeTime:
+		f.AddTime(key, values.([]time.Time), comparasion)
+	}
+
+	for i := range f.keys {
+		if f.keys[i].key == key {
+			return ruleNode{key: i, rule: len(f.keys[i].rules) - 1}
+		}
+	}
+
+	return ruleNode{}
 }
 
 type filterKey struct {
@@ -205,11 +332,73 @@ type filterKey struct {
 	rules []filterRule
 }
 
+// hasType tells if the key stores values of the type, keys without values
+// are considered to be of any type.
+func (k filterKey) hasType(ruleType ruleType) bool {
+	return k.Type == ruleType || k.Type == ruleTypeNone
+}
+
 type filterRule struct {
 	Comparasion ComparasionType
 	indices     []int
 }
 
+// ruleNode is a node of the boolean expression of a filter. Leaves point to a
+// rule of a key, while the other nodes combine their children.
+type ruleNode struct {
+	op       logicalOp
+	key      int
+	rule     int
+	children []ruleNode
+}
+
+// hasOr tells if any node of the tree is an OR.
+func (n ruleNode) hasOr() bool {
+	if n.op == logicalOr {
+		return true
+	}
+
+	for i := range n.children {
+		if n.children[i].hasOr() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prune removes the leaves that doesn't satisfy keep, along with the nodes
+// that became empty. It returns false if the whole tree was removed.
+func (n ruleNode) prune(keep func(leaf ruleNode) bool) (ruleNode, bool) {
+	if n.op == logicalLeaf {
+		return n, keep(n)
+	}
+
+	pruned := ruleNode{op: n.op}
+	for i := range n.children {
+		if child, ok := n.children[i].prune(keep); ok {
+			pruned.children = append(pruned.children, child)
+		}
+	}
+
+	switch len(pruned.children) {
+	case 0:
+		return pruned, false
+	case 1:
+		return pruned.children[0], true
+	}
+
+	return pruned, true
+}
+
+type logicalOp uint8
+
+const (
+	logicalLeaf logicalOp = iota
+	logicalAnd
+	logicalOr
+)
+
 type ruleType uint8
 
 const (
@@ -218,4 +407,5 @@ const (
 	ruleTypeFloat
 	ruleTypeString
 	ruleTypeTime
+	ruleTypeNone
 )
@@ -2,8 +2,12 @@ package qfl_test
 
 import (
 	"fmt"
+	"testing"
+	"time"
 
 	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func ExampleFilter() {
@@ -23,3 +27,45 @@ func ExampleFilter() {
 	// age MoreThan 22
 	// name Like John
 }
+
+func TestFilterAddNull(t *testing.T) {
+	f := qfl.Filter{}
+	f.AddNull("deleted_at", qfl.ComparasionIsNull)
+
+	// Keys without values can be read as any type
+	assert.Equal(t, []qfl.FilterRule[time.Time]{{Comparasion: qfl.ComparasionIsNull, Values: []time.Time{}}}, f.GetTime("deleted_at"))
+	assert.Equal(t, 1, len(f.GetInt("deleted_at")))
+
+	f.AddTime("deleted_at", []time.Time{time.Unix(0, 0)}, qfl.ComparasionMoreThan)
+	assert.Equal(t, 2, len(f.GetTime("deleted_at")))
+	assert.Nil(t, f.GetInt("deleted_at"))
+}
+
+func TestFilterCopy(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+
+	f, err := parser.Parse(map[string]string{"age": "lt!18~gt!65"})
+	assert.NoError(t, err)
+
+	// Adding rules to a copy must not change the tree or values of the original
+	g := *f
+	g.AddString("name", []string{"john"}, qfl.ComparasionEquals)
+	g.AddInt("age", []int{30}, qfl.ComparasionNotEquals)
+
+	h := *f
+	h.AddInt("age", []int{40}, qfl.ComparasionNotEquals)
+
+	assert.Len(t, f.GetInt("age"), 2)
+	assert.Nil(t, f.GetString("name"))
+	assert.Equal(t, []int{30}, g.GetInt("age")[2].Values)
+	assert.Equal(t, []int{40}, h.GetInt("age")[2].Values)
+
+	builder := qfl.SQLBuilder{Filter: *f, Keys: map[string]string{"age": "age"}}
+	assert.NotPanics(t, func() { builder.Where() })
+	assert.Equal(t, "WHERE age < ? OR age > ?\n", builder.Builder.String())
+
+	match, err := f.Match(map[string]any{"age": 30})
+	assert.NoError(t, err)
+	assert.False(t, match)
+}
@@ -0,0 +1,392 @@
+package qfl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// jsonFilter is the JSON of a filter, which follows the schema below. Fields
+// other than `keys` are omitted when the filter doesn't have them, and the
+// expression must have every rule once without empty groups.
+//
+//	{
+//	  "keys": [{
+//	    "key": "age",
+//	    "type": "int",           // int, uint, float, string, time or none
+//	    "rules": [{
+//	      "comparison": "bt",    // eq, ne, lt, gt, le, ge, lk, nl, bt, bx, null or notnull
+//	      "values": [18, 60]     // times are strings in RFC3339, null rules have none
+//	    }]
+//	  }],
+//	  "expression": {            // how the rules are combined, when not all by AND
+//	    "op": "or",              // and, or
+//	    "children": [{"key": "age", "rule": 1}, {"op": "and", "children": [...]}]
+//	  },
+//	  "sort": [{"key": "age", "descending": true, "nulls": "last"}],
+//	  "cursor": {"values": [{"t": "int", "v": "20"}], "backward": true},
+//	  "page": {"limit": 20, "number": 3},
+//	  "fields": ["id", "age"]
+//	}
+type jsonFilter struct {
+	Keys       []jsonKey   `json:"keys"`
+	Expression *jsonNode   `json:"expression,omitempty"`
+	Sort       []jsonSort  `json:"sort,omitempty"`
+	Cursor     *jsonCursor `json:"cursor,omitempty"`
+	Page       *jsonPage   `json:"page,omitempty"`
+	Fields     []string    `json:"fields,omitempty"`
+}
+
+type jsonKey struct {
+	Key   string     `json:"key"`
+	Type  string     `json:"type"`
+	Rules []jsonRule `json:"rules"`
+}
+
+type jsonRule struct {
+	Comparison string          `json:"comparison"`
+	Values     json.RawMessage `json:"values,omitempty"`
+}
+
+type jsonNode struct {
+	Op       string     `json:"op,omitempty"`
+	Key      string     `json:"key,omitempty"`
+	Rule     int        `json:"rule,omitempty"`
+	Children []jsonNode `json:"children,omitempty"`
+}
+
+type jsonSort struct {
+	Key        string `json:"key"`
+	Descending bool   `json:"descending,omitempty"`
+	Nulls      string `json:"nulls,omitempty"`
+}
+
+type jsonCursor struct {
+	Values   []cursorValue `json:"values"`
+	Backward bool          `json:"backward,omitempty"`
+}
+
+type jsonPage struct {
+	Limit  uint64 `json:"limit"`
+	Number uint64 `json:"number"`
+}
+
+var jsonTypes = []string{
+	ruleTypeInt:    "int",
+	ruleTypeUint:   "uint",
+	ruleTypeFloat:  "float",
+	ruleTypeString: "string",
+	ruleTypeTime:   "time",
+	ruleTypeNone:   "none",
+}
+
+var jsonComparisons = []string{
+	ComparasionEquals:           "eq",
+	ComparasionLessThan:         "lt",
+	ComparasionMoreThan:         "gt",
+	ComparasionLessOrEqual:      "le",
+	ComparasionMoreOrEqual:      "ge",
+	ComparasionLike:             "lk",
+	ComparasionNotEquals:        "ne",
+	ComparasionNotLike:          "nl",
+	ComparasionIsNull:           "null",
+	ComparasionIsNotNull:        "notnull",
+	ComparasionBetween:          "bt",
+	ComparasionBetweenExclusive: "bx",
+}
+
+// jsonComparison returns the name of the comparasion, or false if it isn't a
+// valid one.
+func jsonComparison(comparasion ComparasionType) (string, bool) {
+	if int(comparasion) >= len(jsonComparisons) || jsonComparisons[comparasion] == "" {
+		return "", false
+	}
+
+	return jsonComparisons[comparasion], true
+}
+
+var jsonNulls = []string{
+	SortNullsDefault: "",
+	SortNullsFirst:   "first",
+	SortNullsLast:    "last",
+}
+
+// MarshalJSON encodes the filter with the schema of `jsonFilter`, keeping the
+// type of each key so decoding it returns the same typed rules.
+func (f Filter) MarshalJSON() ([]byte, error) {
+	jf := jsonFilter{Keys: make([]jsonKey, len(f.keys)), Fields: f.fields}
+	for i, key := range f.keys {
+		jf.Keys[i] = jsonKey{Key: key.key, Type: jsonTypes[key.Type], Rules: make([]jsonRule, len(key.rules))}
+		for j, rule := range key.rules {
+			values, err := f.marshalValues(key.Type, rule)
+			if err != nil {
+				return nil, fmt.Errorf("key `%s`: %w", key.key, err)
+			}
+
+			comparison, ok := jsonComparison(rule.Comparasion)
+			if !ok {
+				return nil, fmt.Errorf("key `%s` has unknown comparasion %d", key.key, rule.Comparasion)
+			}
+
+			jf.Keys[i].Rules[j] = jsonRule{Comparison: comparison, Values: values}
+		}
+	}
+
+	if f.expr != nil {
+		node := f.marshalNode(*f.expr)
+		jf.Expression = &node
+	}
+
+	for _, field := range f.sort {
+		if int(field.Nulls) >= len(jsonNulls) {
+			return nil, fmt.Errorf("sort field `%s` has unknown nulls %d", field.Key, field.Nulls)
+		}
+
+		jf.Sort = append(jf.Sort, jsonSort{field.Key, field.Descending, jsonNulls[field.Nulls]})
+	}
+
+	if f.cursor != nil && len(f.cursor.Values) != len(f.sort) {
+		return nil, fmt.Errorf("cursor has %d values, but the filter has %d sort fields", len(f.cursor.Values), len(f.sort))
+	} else if f.cursor != nil {
+		jf.Cursor = &jsonCursor{Backward: f.cursor.Backward, Values: []cursorValue{}}
+		for i, value := range f.cursor.Values {
+			cv, err := newCursorValue(f.sort[i].Key, reflect.ValueOf(value))
+			if err != nil {
+				return nil, err
+			}
+
+			jf.Cursor.Values = append(jf.Cursor.Values, cv)
+		}
+	}
+
+	if f.page != nil {
+		jf.Page = &jsonPage{f.page.Limit, f.page.Number}
+	}
+
+	return json.Marshal(jf)
+}
+
+// UnmarshalJSON decodes a filter with the schema of `jsonFilter`, replacing
+// the content of the filter.
+func (f *Filter) UnmarshalJSON(data []byte) error {
+	jf := jsonFilter{}
+	if err := json.Unmarshal(data, &jf); err != nil {
+		return err
+	}
+
+	decoded := Filter{fields: jf.Fields}
+	for _, key := range jf.Keys {
+		typ := slices.Index(jsonTypes, key.Type)
+		if typ == -1 {
+			return fmt.Errorf("key `%s` has unknown type `%s`", key.Key, key.Type)
+		} else if slices.ContainsFunc(decoded.keys, func(k filterKey) bool { return k.key == key.Key }) {
+			return fmt.Errorf("key `%s` is repeated", key.Key)
+		}
+
+		for _, rule := range key.Rules {
+			if err := decoded.unmarshalRule(key.Key, ruleType(typ), rule); err != nil {
+				return fmt.Errorf("key `%s`: %w", key.Key, err)
+			}
+		}
+
+		// Keys without rules are kept, so their type isn't lost
+		if len(key.Rules) == 0 {
+			decoded.keys = append(decoded.keys, filterKey{key: key.Key, Type: ruleType(typ)})
+		}
+	}
+
+	if jf.Expression != nil {
+		seen := map[[2]int]bool{}
+		node, err := decoded.unmarshalNode(*jf.Expression, seen)
+		if err != nil {
+			return err
+		}
+
+		// Rules left out of the expression would be dropped by the writers,
+		// widening the filter
+		for i, key := range decoded.keys {
+			for j := range key.rules {
+				if !seen[[2]int{i, j}] {
+					return fmt.Errorf("expression doesn't have rule %d of key `%s`", j, key.key)
+				}
+			}
+		}
+
+		decoded.expr = &node
+	}
+
+	for _, field := range jf.Sort {
+		nulls := slices.Index(jsonNulls, field.Nulls)
+		if nulls == -1 {
+			return fmt.Errorf("sort field `%s` has unknown nulls `%s`", field.Key, field.Nulls)
+		}
+
+		decoded.sort = append(decoded.sort, SortField{field.Key, field.Descending, SortNulls(nulls)})
+	}
+
+	if jf.Cursor != nil {
+		decoded.cursor = &Cursor{Backward: jf.Cursor.Backward}
+		for _, cv := range jf.Cursor.Values {
+			value, err := cv.parse()
+			if err != nil {
+				return err
+			}
+
+			decoded.cursor.Values = append(decoded.cursor.Values, value)
+		}
+	}
+
+	if jf.Page != nil {
+		decoded.page = &Page{Limit: jf.Page.Limit, Number: jf.Page.Number}
+	}
+
+	*f = decoded
+	return nil
+}
+
+func (f *Filter) marshalValues(ruleType ruleType, rule filterRule) (json.RawMessage, error) {
+	if len(rule.indices) == 0 {
+		return nil, nil
+	}
+
+	var values any
+	switch ruleType {
+	case ruleTypeInt:
+		values = ruleValues(rule, f.intVals)
+	case ruleTypeUint:
+		values = ruleValues(rule, f.uintVals)
+	case ruleTypeFloat:
+		values = ruleValues(rule, f.floatVals)
+	case ruleTypeString:
+		values = ruleValues(rule, f.stringVals)
+	case ruleTypeTime:
+		values = ruleValues(rule, f.timeVals)
+	}
+
+	return json.Marshal(values)
+}
+
+// unmarshalRule adds the rule using the typed function of the key, checking
+// that it has the amount of values of its comparasion.
+func (f *Filter) unmarshalRule(key string, ruleType ruleType, rule jsonRule) error {
+	index := slices.Index(jsonComparisons, rule.Comparison)
+	if rule.Comparison == "" || index == -1 {
+		return fmt.Errorf("rule has unknown comparison `%s`", rule.Comparison)
+	}
+
+	comparasion := ComparasionType(index)
+
+	if comparasion == ComparasionIsNull || comparasion == ComparasionIsNotNull {
+		if len(rule.Values) != 0 {
+			return fmt.Errorf("comparison `%s` can't have values", rule.Comparison)
+		}
+
+		f.appendRule(key, nil, comparasion, ruleType)
+		return nil
+	}
+
+	var err error
+	var count int
+	switch ruleType {
+	case ruleTypeInt:
+		count, err = unmarshalValues(rule.Values, key, comparasion, f.AddInt)
+	case ruleTypeUint:
+		count, err = unmarshalValues(rule.Values, key, comparasion, f.AddUint)
+	case ruleTypeFloat:
+		count, err = unmarshalValues(rule.Values, key, comparasion, f.AddFloat)
+	case ruleTypeString:
+		count, err = unmarshalValues(rule.Values, key, comparasion, f.AddString)
+	case ruleTypeTime:
+		count, err = unmarshalValues(rule.Values, key, comparasion, f.AddTime)
+	default:
+		return fmt.Errorf("keys of type `none` can only have null rules")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case count == 0:
+		return fmt.Errorf("comparison `%s` has no values", rule.Comparison)
+	case comparasion.isRange() && count != 2:
+		return fmt.Errorf("comparison `%s` must have 2 values, it has %d", rule.Comparison, count)
+	case !comparasion.isList() && count != 1:
+		return fmt.Errorf("comparison `%s` must have 1 value, it has %d", rule.Comparison, count)
+	}
+
+	return nil
+}
+
+// unmarshalValues decodes the values and adds them with the typed function,
+// returning how many values were added. Times are decoded from RFC3339 by
+// their own unmarshaler.
+func unmarshalValues[T Primitive](data json.RawMessage, key string, comparasion ComparasionType, add func(string, []T, ComparasionType)) (int, error) {
+	values := []T{}
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return 0, err
+		}
+	}
+
+	add(key, values, comparasion)
+	return len(values), nil
+}
+
+func (f *Filter) marshalNode(node ruleNode) jsonNode {
+	if node.op == logicalLeaf {
+		return jsonNode{Key: f.keys[node.key].key, Rule: node.rule}
+	}
+
+	op := "and"
+	if node.op == logicalOr {
+		op = "or"
+	}
+
+	jn := jsonNode{Op: op, Children: make([]jsonNode, len(node.children))}
+	for i := range node.children {
+		jn.Children[i] = f.marshalNode(node.children[i])
+	}
+
+	return jn
+}
+
+// unmarshalNode decodes the node, checking that its leaves point to rules of
+// the filter that aren't in seen, and that its groups aren't empty.
+func (f *Filter) unmarshalNode(jn jsonNode, seen map[[2]int]bool) (ruleNode, error) {
+	switch jn.Op {
+	case "":
+		key := slices.IndexFunc(f.keys, func(k filterKey) bool { return k.key == jn.Key })
+		if key == -1 || jn.Rule < 0 || jn.Rule >= len(f.keys[key].rules) {
+			return ruleNode{}, fmt.Errorf("expression has unknown rule %d of key `%s`", jn.Rule, jn.Key)
+		} else if seen[[2]int{key, jn.Rule}] {
+			return ruleNode{}, fmt.Errorf("expression has rule %d of key `%s` more than once", jn.Rule, jn.Key)
+		}
+
+		seen[[2]int{key, jn.Rule}] = true
+		return ruleNode{key: key, rule: jn.Rule}, nil
+	case "and", "or":
+		node := ruleNode{op: logicalAnd}
+		if jn.Op == "or" {
+			node.op = logicalOr
+		}
+
+		if len(jn.Children) == 0 {
+			return ruleNode{}, fmt.Errorf("expression has an empty `%s` group", jn.Op)
+		}
+
+		for _, child := range jn.Children {
+			n, err := f.unmarshalNode(child, seen)
+			if err != nil {
+				return ruleNode{}, err
+			}
+
+			node.children = append(node.children, n)
+		}
+
+		return node, nil
+	}
+
+	return ruleNode{}, fmt.Errorf("expression has unknown operator `%s`", jn.Op)
+}
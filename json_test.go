@@ -0,0 +1,148 @@
+package qfl_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleFilter_MarshalJSON() {
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{18, 60}, qfl.ComparasionBetween)
+	filter.AddNull("name", qfl.ComparasionIsNotNull)
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(string(data))
+	// Output:
+	// {"keys":[{"key":"age","type":"int","rules":[{"comparison":"bt","values":[18,60]}]},{"key":"name","type":"none","rules":[{"comparison":"notnull"}]}]}
+}
+
+func TestFilterJSON(t *testing.T) {
+	parser := qfl.Parser{
+		ExpressionKey: "q",
+		SortKey:       "sort",
+		FieldsKey:     "fields",
+		Pagination:    &qfl.Pagination{DefaultSize: 10, MaxSize: 100},
+	}
+	parser.AddInt("age")
+	parser.AddUint("id")
+	parser.AddFloat("score")
+	parser.AddString("name")
+	parser.AddTime("created")
+	parser.AddSort("id", "age")
+	parser.AddField("id", "name")
+
+	cases := []map[string]string{
+		{},
+		{"age": "gt!20|lt!60", "id": "eq!1,2,3", "score": "bx!0.5,1.5"},
+		{"name": "is!null~lk!jo%", "created": "ge!2024-01-02T03:04:05.5Z"},
+		{"age": "(lt!18~gt!60)|ne!99", "q": "name:a or (id:1 and age:5)"},
+		{"sort": "-id!last,age", "fields": "name,id", "limit": "20", "page": "2"},
+	}
+
+	for _, c := range cases {
+		fm, err := parser.Parse(c)
+		if !assert.NoError(t, err, c) {
+			continue
+		}
+
+		data, err := json.Marshal(fm)
+		if !assert.NoError(t, err, c) {
+			continue
+		}
+
+		decoded := qfl.Filter{}
+		if !assert.NoError(t, json.Unmarshal(data, &decoded), c) {
+			continue
+		}
+
+		// Values may be stored in a different order, so the filters are
+		// compared by their contents
+		assert.Equal(t, fm.GetInt("age"), decoded.GetInt("age"), c)
+		assert.Equal(t, fm.GetUint("id"), decoded.GetUint("id"), c)
+		assert.Equal(t, fm.GetFloat("score"), decoded.GetFloat("score"), c)
+		assert.Equal(t, fm.GetString("name"), decoded.GetString("name"), c)
+		assert.Equal(t, fm.GetTime("created"), decoded.GetTime("created"), c)
+		assert.Equal(t, fm.GetSort(), decoded.GetSort(), c)
+		assert.Equal(t, fm.GetFields(), decoded.GetFields(), c)
+		assert.Equal(t, fm.GetPage(), decoded.GetPage(), c)
+
+		expected, err := parser.Encode(fm)
+		assert.NoError(t, err, c)
+
+		got, err := parser.Encode(&decoded)
+		assert.NoError(t, err, c)
+		assert.Equal(t, expected, got, c)
+	}
+}
+
+func TestFilterJSONCursor(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	filter := qfl.Filter{}
+	filter.AddSort(qfl.SortField{Key: "created"}, qfl.SortField{Key: "id"})
+	filter.SetCursor(&qfl.Cursor{Values: []any{created, uint(10)}, Backward: true})
+
+	data, err := json.Marshal(filter)
+	assert.NoError(t, err)
+
+	decoded := qfl.Filter{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, filter.GetCursor(), decoded.GetCursor())
+}
+
+func TestFilterJSONInvalid(t *testing.T) {
+	cases := []string{
+		`{"keys":[{"key":"age","type":"bool","rules":[]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"xx","values":[1]}]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"eq","values":["1"]}]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"eq","values":[]}]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"gt","values":[1,2]}]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"bt","values":[1]}]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"null","values":[1]}]}]}`,
+		`{"keys":[{"key":"age","type":"none","rules":[{"comparison":"eq","values":[1]}]}]}`,
+		`{"keys":[{"key":"age","type":"time","rules":[{"comparison":"eq","values":["yesterday"]}]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[]},{"key":"age","type":"int","rules":[]}]}`,
+		`{"keys":[{"key":"age","type":"int","rules":[]}],"expression":{"key":"age","rule":0}}`,
+		`{"keys":[],"expression":{"op":"xor"}}`,
+		`{"keys":[],"expression":{"op":"or","children":[]}}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"eq","values":[1]},{"comparison":"eq","values":[2]}]}],"expression":{"op":"or","children":[{"key":"age","rule":0}]}}`,
+		`{"keys":[{"key":"age","type":"int","rules":[{"comparison":"eq","values":[1]}]}],"expression":{"op":"or","children":[{"key":"age"},{"key":"age"}]}}`,
+		`{"keys":[],"sort":[{"key":"age","nulls":"middle"}]}`,
+		`{"keys":[],"cursor":{"values":[{"t":"bool","v":"true"}]}}`,
+	}
+
+	for _, c := range cases {
+		filter := qfl.Filter{}
+		assert.Error(t, json.Unmarshal([]byte(c), &filter), c)
+	}
+}
+
+func TestFilterJSONUnknownComparasion(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{1}, qfl.ComparasionType(99))
+
+	_, err := json.Marshal(filter)
+	assert.ErrorContains(t, err, "key `age` has unknown comparasion 99")
+
+	filter = qfl.Filter{}
+	filter.AddInt("age", []int{1}, qfl.ComparasionInvalid)
+
+	_, err = json.Marshal(filter)
+	assert.Error(t, err)
+
+	filter = qfl.Filter{}
+	filter.AddSort(qfl.SortField{Key: "age", Nulls: qfl.SortNulls(9)})
+
+	_, err = json.Marshal(filter)
+	assert.ErrorContains(t, err, "sort field `age` has unknown nulls 9")
+}
@@ -0,0 +1,333 @@
+package qfl
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Match reports whether the record satisfies every rule of the filter. The
+// record can either be a `map[string]any` or a struct (or a pointer to one),
+// in which case the key is looked up on the `qfl` tag of the fields, falling
+// back to the field name when there is no tag.
+//
+// A key that is absent from the map, or whose value is a nil pointer, is
+// considered null and only matches `ComparasionIsNull`. A key that doesn't exist
+// on the struct is an error.
+func (f *Filter) Match(record any) (bool, error) {
+	rv, err := recordValue(record)
+	if err != nil {
+		return false, err
+	}
+
+	return f.matchNode(rv, f.expression())
+}
+
+// recordValue returns the struct or map behind the pointers of the record.
+func recordValue(record any) (reflect.Value, error) {
+	rv := reflect.ValueOf(record)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return rv, fmt.Errorf("record is nil")
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map {
+		return rv, fmt.Errorf("record of type `%s` is not a struct or map", rv.Type())
+	}
+
+	return rv, nil
+}
+
+func (f *Filter) matchNode(record reflect.Value, node ruleNode) (bool, error) {
+	switch node.op {
+	case logicalAnd, logicalOr:
+		// AND stops on the first mismatch, while OR stops on the first match
+		for i := range node.children {
+			ok, err := f.matchNode(record, node.children[i])
+			if err != nil || ok == (node.op == logicalOr) {
+				return ok, err
+			}
+		}
+
+		return node.op == logicalAnd, nil
+	}
+
+	key := f.keys[node.key]
+	field, err := lookupField(record, key.key)
+	if err != nil {
+		return false, err
+	}
+
+	return f.matchRule(key, key.rules[node.rule], field)
+}
+
+func (f *Filter) matchRule(key filterKey, rule filterRule, field reflect.Value) (bool, error) {
+	switch rule.Comparasion {
+	case ComparasionIsNull:
+		return !field.IsValid(), nil
+	case ComparasionIsNotNull:
+		return field.IsValid(), nil
+	}
+
+	switch key.Type {
+	case ruleTypeInt:
+		return matchGeneric(key.key, rule, f.intVals, field)
+	case ruleTypeUint:
+		return matchGeneric(key.key, rule, f.uintVals, field)
+	case ruleTypeFloat:
+		return matchGeneric(key.key, rule, f.floatVals, field)
+	case ruleTypeString:
+		return matchGeneric(key.key, rule, f.stringVals, field)
+	case ruleTypeTime:
+		return matchGeneric(key.key, rule, f.timeVals, field)
+	}
+
+	return false, fmt.Errorf("unexpected pkg.RuleType: %#v", key.Type)
+}
+
+// FilterSlice returns the records that match the filter, keeping their order.
+func FilterSlice[R any](f *Filter, records []R) ([]R, error) {
+	matched := []R{}
+	for i := range records {
+		ok, err := f.Match(records[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			matched = append(matched, records[i])
+		}
+	}
+
+	return matched, nil
+}
+
+// FilterSeq lazily yields the records of the sequence that match the filter.
+// If a record can't be evaluated, it yields the error along with the record
+// and stops.
+func FilterSeq[R any](f *Filter, records iter.Seq[R]) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		for record := range records {
+			ok, err := f.Match(record)
+			if err != nil {
+				yield(record, err)
+				return
+			}
+
+			if ok && !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// lookupField finds the value stored for the key inside the record. It returns
+// an invalid value when the record doesn't hold any value for it.
+func lookupField(record reflect.Value, key string) (reflect.Value, error) {
+	var field reflect.Value
+
+	if record.Kind() == reflect.Map {
+		if record.Type().Key().Kind() != reflect.String {
+			return field, fmt.Errorf("map of type `%s` doesn't have string keys", record.Type())
+		}
+
+		field = record.MapIndex(reflect.ValueOf(key).Convert(record.Type().Key()))
+	} else {
+		idx, ok := fieldIndex(record.Type(), key)
+		if !ok {
+			return field, fmt.Errorf("struct `%s` has no field for key `%s`", record.Type(), key)
+		}
+
+		field, _ = record.FieldByIndexErr(idx)
+	}
+
+	for field.IsValid() && (field.Kind() == reflect.Pointer || field.Kind() == reflect.Interface) {
+		if field.IsNil() {
+			return reflect.Value{}, nil
+		}
+		field = field.Elem()
+	}
+
+	return field, nil
+}
+
+// fieldIndex returns the index of the struct field named by the key, either by
+// its `qfl` tag or by its name.
+func fieldIndex(t reflect.Type, key string) ([]int, bool) {
+	var byName []int
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(field.Tag.Get("qfl"), ",")
+		if tag == key {
+			return field.Index, true
+		} else if tag == "" && field.Name == key {
+			byName = field.Index
+		}
+	}
+
+	return byName, byName != nil
+}
+
+func matchGeneric[T Primitive](key string, rule filterRule, values []T, field reflect.Value) (bool, error) {
+	if !field.IsValid() {
+		return false, nil
+	}
+
+	value, err := convertField[T](key, field)
+	if err != nil {
+		return false, err
+	}
+
+	switch rule.Comparasion {
+	case ComparasionEquals, ComparasionNotEquals:
+		found := false
+		for i := range rule.indices {
+			if compare(value, values[rule.indices[i]]) == 0 {
+				found = true
+				break
+			}
+		}
+
+		return found == (rule.Comparasion == ComparasionEquals), nil
+	case ComparasionLessThan:
+		return compare(value, values[rule.indices[0]]) < 0, nil
+	case ComparasionMoreThan:
+		return compare(value, values[rule.indices[0]]) > 0, nil
+	case ComparasionLessOrEqual:
+		return compare(value, values[rule.indices[0]]) <= 0, nil
+	case ComparasionMoreOrEqual:
+		return compare(value, values[rule.indices[0]]) >= 0, nil
+	case ComparasionLike:
+		return matchLike(fmt.Sprint(value), fmt.Sprint(values[rule.indices[0]])), nil
+	case ComparasionNotLike:
+		return !matchLike(fmt.Sprint(value), fmt.Sprint(values[rule.indices[0]])), nil
+	case ComparasionBetween:
+		return compare(value, values[rule.indices[0]]) >= 0 && compare(value, values[rule.indices[1]]) <= 0, nil
+	case ComparasionBetweenExclusive:
+		return compare(value, values[rule.indices[0]]) > 0 && compare(value, values[rule.indices[1]]) < 0, nil
+	}
+
+	return false, fmt.Errorf("unexpected comparasion %s on key `%s`", rule.Comparasion, key)
+}
+
+// convertField converts the value of the record into the type of the key, so
+// they can be compared.
+func convertField[T Primitive](key string, field reflect.Value) (T, error) {
+	var (
+		result T
+		value  any
+	)
+
+	switch any(result).(type) {
+	case int:
+		switch {
+		case field.CanInt():
+			value = int(field.Int())
+		case field.CanUint():
+			value = int(field.Uint())
+		case field.CanFloat() && field.Float() == float64(int(field.Float())):
+			value = int(field.Float())
+		}
+	case uint:
+		switch {
+		case field.CanUint():
+			value = uint(field.Uint())
+		case field.CanInt() && field.Int() >= 0:
+			value = uint(field.Int())
+		case field.CanFloat() && field.Float() >= 0 && field.Float() == float64(uint(field.Float())):
+			value = uint(field.Float())
+		}
+	case float64:
+		switch {
+		case field.CanFloat():
+			value = field.Float()
+		case field.CanInt():
+			value = float64(field.Int())
+		case field.CanUint():
+			value = float64(field.Uint())
+		}
+	case string:
+		if field.Kind() == reflect.String {
+			value = field.String()
+		}
+	case time.Time:
+		if t, ok := field.Interface().(time.Time); ok {
+			value = t
+		}
+	}
+
+	if value == nil {
+		return result, fmt.Errorf("value of type `%s` for key `%s` can't be compared with %T", field.Type(), key, result)
+	}
+
+	return value.(T), nil
+}
+
+func compare[T Primitive](a, b T) int {
+	switch a := any(a).(type) {
+	case time.Time:
+		return a.Compare(any(b).(time.Time))
+	case int:
+		return cmp.Compare(a, any(b).(int))
+	case uint:
+		return cmp.Compare(a, any(b).(uint))
+	case float64:
+		return cmp.Compare(a, any(b).(float64))
+	case string:
+		return cmp.Compare(a, any(b).(string))
+	}
+
+	return 0
+}
+
+// matchLike matches the string against a SQL LIKE pattern, where `%` matches
+// any sequence of characters, `_` matches a single character and `\` escapes
+// the character in front of it.
+func matchLike(str, pattern string) bool {
+	s, p := []rune(str), []rune(pattern)
+
+	// Position to backtrack when the last `%` needs to consume one more rune
+	starP, starS := -1, 0
+	i, j := 0, 0
+	for i < len(s) {
+		if j < len(p) && p[j] == '%' {
+			starP, starS = j, i
+			j++
+			continue
+		}
+
+		if j < len(p) {
+			literal, width := p[j], 1
+			if literal == '\\' && j+1 < len(p) {
+				literal, width = p[j+1], 2
+			}
+
+			if (width == 1 && literal == '_') || literal == s[i] {
+				i++
+				j += width
+				continue
+			}
+		}
+
+		if starP == -1 {
+			return false
+		}
+
+		starS++
+		i, j = starS, starP+1
+	}
+
+	for j < len(p) && p[j] == '%' {
+		j++
+	}
+
+	return j == len(p)
+}
@@ -0,0 +1,153 @@
+package qfl_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type employee struct {
+	Name          string `qfl:"name"`
+	Age           int    `qfl:"age"`
+	Salary        float64
+	EmployedSince *time.Time `qfl:"employedSince"`
+}
+
+func ExampleFilterSlice() {
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{20}, qfl.ComparasionMoreThan)
+	filter.AddString("name", []string{"Ro%"}, qfl.ComparasionLike)
+
+	employees := []employee{
+		{Name: "Roberto", Age: 23},
+		{Name: "Rodrigo", Age: 19},
+		{Name: "Alice", Age: 30},
+	}
+
+	matched, err := qfl.FilterSlice(&filter, employees)
+	if err != nil {
+		// Treat error...
+	}
+
+	for _, e := range matched {
+		fmt.Println(e.Name, e.Age)
+	}
+	// Output:
+	// Roberto 23
+}
+
+func TestMatchStruct(t *testing.T) {
+	since := time.Date(2023, 5, 2, 9, 34, 1, 0, time.UTC)
+
+	filter := qfl.Filter{}
+	filter.AddString("name", []string{"John", "Mary"}, qfl.ComparasionEquals)
+	filter.AddFloat("Salary", []float64{3000}, qfl.ComparasionMoreOrEqual)
+	filter.AddTime("employedSince", []time.Time{since}, qfl.ComparasionLessOrEqual)
+
+	ok, err := filter.Match(employee{Name: "Mary", Salary: 3000, EmployedSince: &since})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = filter.Match(&employee{Name: "Bob", Salary: 3000, EmployedSince: &since})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Nil pointers never match
+	ok, err = filter.Match(employee{Name: "John", Salary: 4000})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	missing := qfl.Filter{}
+	missing.AddInt("missing", []int{1}, qfl.ComparasionEquals)
+	_, err = missing.Match(employee{})
+	assert.Error(t, err)
+}
+
+func TestMatchMap(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddUint("age", []uint{18}, qfl.ComparasionMoreThan)
+	filter.AddUint("age", []uint{65}, qfl.ComparasionLessThan)
+
+	ok, err := filter.Match(map[string]any{"age": 30.0})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = filter.Match(map[string]any{"age": 70})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = filter.Match(map[string]any{"name": "John"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = filter.Match(map[string]any{"age": "thirty"})
+	assert.Error(t, err)
+}
+
+func TestMatchLike(t *testing.T) {
+	cases := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{"John", "John", true},
+		{"Jo%", "John", true},
+		{"%hn", "John", true},
+		{"%o%", "John", true},
+		{"J_hn", "John", true},
+		{"J_n", "John", false},
+		{"%x%", "John", false},
+		{"%", "", true},
+		{`100\%`, "100%", true},
+		{`100\%`, "1000", false},
+		{"a%b%c", "abbbc", true},
+		{"a%b%c", "abbbd", false},
+	}
+
+	for _, c := range cases {
+		filter := qfl.Filter{}
+		filter.AddString("name", []string{c.pattern}, qfl.ComparasionLike)
+
+		ok, err := filter.Match(map[string]string{"name": c.value})
+		assert.NoError(t, err)
+		assert.Equal(t, c.match, ok, "`%s` LIKE `%s`", c.value, c.pattern)
+	}
+}
+
+func TestFilterSeq(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{20}, qfl.ComparasionLessThan)
+
+	ages := []map[string]any{{"age": 10}, {"age": 30}, {"age": 15}}
+
+	matched := []int{}
+	for record, err := range qfl.FilterSeq(&filter, slices.Values(ages)) {
+		assert.NoError(t, err)
+		matched = append(matched, record["age"].(int))
+	}
+
+	assert.Equal(t, []int{10, 15}, matched)
+}
+
+func TestMatchRange(t *testing.T) {
+	inclusive := qfl.Filter{}
+	inclusive.AddInt("age", []int{10, 20}, qfl.ComparasionBetween)
+
+	exclusive := qfl.Filter{}
+	exclusive.AddInt("age", []int{10, 20}, qfl.ComparasionBetweenExclusive)
+
+	for age, expected := range map[int][2]bool{9: {false, false}, 10: {true, false}, 15: {true, true}, 20: {true, false}, 21: {false, false}} {
+		ok, err := inclusive.Match(map[string]any{"age": age})
+		assert.NoError(t, err)
+		assert.Equal(t, expected[0], ok, "bt %d", age)
+
+		ok, err = exclusive.Match(map[string]any{"age": age})
+		assert.NoError(t, err)
+		assert.Equal(t, expected[1], ok, "bx %d", age)
+	}
+}
@@ -0,0 +1,170 @@
+package qfl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MongoBuilder converts the rules of a filter into a MongoDB filter document.
+// It doesn't depend on the driver, which accepts the returned map as is.
+//
+// Keys are mapped to the fields of `Keys`, and keys that aren't in it are
+// skipped unless `Strict` is set. When `Keys` is nil, keys are used as the
+// name of their fields.
+type MongoBuilder struct {
+	Filter          Filter
+	Keys            map[string]string
+	Strict          bool // error on keys of the filter that aren't in Keys
+	CaseInsensitive bool // match LIKE patterns ignoring the case
+}
+
+// Build returns the filter document, which is empty when there are no
+// conditions.
+func (mb *MongoBuilder) Build() (map[string]any, error) {
+	root, ok, err := mb.Filter.mappedExpression(mb.Keys, mb.Strict, "field")
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return map[string]any{}, nil
+	}
+
+	return mb.buildNode(root), nil
+}
+
+func (mb *MongoBuilder) buildNode(node ruleNode) map[string]any {
+	if node.op != logicalLeaf {
+		operator := "$and"
+		if node.op == logicalOr {
+			operator = "$or"
+		}
+
+		return map[string]any{operator: mb.buildChildren(node, []any{})}
+	}
+
+	key := mb.Filter.keys[node.key]
+	rule := key.rules[node.rule]
+	field, _ := mappedField(mb.Keys, key.key)
+
+	var values []any
+	switch key.Type {
+	case ruleTypeInt:
+		values = ruleValues(rule, mb.Filter.intVals)
+	case ruleTypeUint:
+		values = ruleValues(rule, mb.Filter.uintVals)
+	case ruleTypeFloat:
+		values = ruleValues(rule, mb.Filter.floatVals)
+	case ruleTypeString:
+		values = ruleValues(rule, mb.Filter.stringVals)
+	case ruleTypeTime:
+		values = ruleValues(rule, mb.Filter.timeVals)
+	}
+
+	return map[string]any{field: mb.condition(rule.Comparasion, values)}
+}
+
+// buildChildren appends the documents of the children of the node, merging
+// the children of nested nodes with the same operator.
+func (mb *MongoBuilder) buildChildren(node ruleNode, children []any) []any {
+	for _, child := range node.children {
+		if child.op == node.op {
+			children = mb.buildChildren(child, children)
+		} else {
+			children = append(children, mb.buildNode(child))
+		}
+	}
+
+	return children
+}
+
+// condition returns the operators of the comparasion for the values.
+func (mb *MongoBuilder) condition(comparasion ComparasionType, values []any) map[string]any {
+	switch comparasion {
+	case ComparasionEquals:
+		if len(values) == 1 {
+			return map[string]any{"$eq": values[0]}
+		}
+
+		return map[string]any{"$in": values}
+	case ComparasionNotEquals:
+		if len(values) == 1 {
+			return map[string]any{"$ne": values[0]}
+		}
+
+		return map[string]any{"$nin": values}
+	case ComparasionLessThan:
+		return map[string]any{"$lt": values[0]}
+	case ComparasionMoreThan:
+		return map[string]any{"$gt": values[0]}
+	case ComparasionLessOrEqual:
+		return map[string]any{"$lte": values[0]}
+	case ComparasionMoreOrEqual:
+		return map[string]any{"$gte": values[0]}
+	case ComparasionLike:
+		return mb.regex(values[0])
+	case ComparasionNotLike:
+		return map[string]any{"$not": mb.regex(values[0])}
+	case ComparasionIsNull:
+		return map[string]any{"$eq": nil}
+	case ComparasionIsNotNull:
+		return map[string]any{"$ne": nil}
+	case ComparasionBetween:
+		return map[string]any{"$gte": values[0], "$lte": values[1]}
+	case ComparasionBetweenExclusive:
+		return map[string]any{"$gt": values[0], "$lt": values[1]}
+	}
+
+	return map[string]any{}
+}
+
+func (mb *MongoBuilder) regex(pattern any) map[string]any {
+	options := "s"
+	if mb.CaseInsensitive {
+		options = "is"
+	}
+
+	return map[string]any{"$regex": likeToRegex(fmt.Sprint(pattern)), "$options": options}
+}
+
+// likeToRegex converts a LIKE pattern into a regular expression that matches
+// the whole string, where `%` matches any sequence of characters, `_` matches
+// a single character and `\` escapes the character in front of it.
+func likeToRegex(pattern string) string {
+	var sb strings.Builder
+	sb.WriteRune('^')
+
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			sb.WriteString(".*")
+		case r == '_':
+			sb.WriteRune('.')
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	// A trailing backslash doesn't escape anything, so it's a literal
+	if escaped {
+		sb.WriteString(`\\`)
+	}
+
+	sb.WriteRune('$')
+	return sb.String()
+}
+
+// ruleValues returns the values of the rule.
+func ruleValues[T Primitive](rule filterRule, values []T) []any {
+	result := make([]any, len(rule.indices))
+	for i := range rule.indices {
+		result[i] = values[rule.indices[i]]
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package qfl_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleMongoBuilder() {
+	filter := qfl.Filter{}
+	filter.AddString("name", []string{"Rob%"}, qfl.ComparasionLike)
+	filter.AddUint("age", []uint{18, 65}, qfl.ComparasionBetween)
+
+	builder := qfl.MongoBuilder{
+		Filter: filter,
+		Keys:   map[string]string{"name": "profile.name", "age": "age"},
+	}
+
+	doc, err := builder.Build()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(doc)
+	// Output:
+	// map[$and:[map[profile.name:map[$options:s $regex:^Rob.*$]] map[age:map[$gte:18 $lte:65]]]]
+}
+
+func TestMongoBuilder(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddString("role")
+	parser.AddTime("deleted_at")
+
+	filter, err := parser.Parse(map[string]string{
+		"age":        "lt!18~gt!65|ne!70,80",
+		"role":       "eq!admin,owner|nl!%test%",
+		"deleted_at": "is!null",
+	})
+	assert.NoError(t, err)
+
+	builder := qfl.MongoBuilder{Filter: *filter}
+	doc, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"$and": []any{
+			map[string]any{"$or": []any{
+				map[string]any{"age": map[string]any{"$lt": 18}},
+				map[string]any{"$and": []any{
+					map[string]any{"age": map[string]any{"$gt": 65}},
+					map[string]any{"age": map[string]any{"$nin": []any{70, 80}}},
+				}},
+			}},
+			map[string]any{"role": map[string]any{"$in": []any{"admin", "owner"}}},
+			map[string]any{"role": map[string]any{"$not": map[string]any{"$regex": "^.*test.*$", "$options": "s"}}},
+			map[string]any{"deleted_at": map[string]any{"$eq": nil}},
+		},
+	}, doc)
+}
+
+func TestMongoBuilderKeys(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := qfl.Filter{}
+	filter.AddTime("created_at", []time.Time{created}, qfl.ComparasionMoreOrEqual)
+	filter.AddString("password", []string{"secret"}, qfl.ComparasionEquals)
+
+	builder := qfl.MongoBuilder{Filter: filter, Keys: map[string]string{"created_at": "createdAt"}}
+	doc, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"createdAt": map[string]any{"$gte": created}}, doc)
+
+	builder.Strict = true
+	_, err = builder.Build()
+	assert.EqualError(t, err, "key `password` isn't mapped to a field")
+
+	builder = qfl.MongoBuilder{Keys: map[string]string{}}
+	doc, err = builder.Build()
+	assert.NoError(t, err)
+	assert.Empty(t, doc)
+}
+
+func TestMongoBuilderLike(t *testing.T) {
+	cases := []struct {
+		pattern string
+		regex   string
+		match   string
+	}{
+		{"J_hn%", "^J.hn.*$", "John Doe"},
+		{"50\\%", "^50%$", "50%"},
+		{"a.b*c", `^a\.b\*c$`, "a.b*c"},
+		{"\\_x\\", `^_x\\$`, `_x\`},
+	}
+
+	for _, c := range cases {
+		filter := qfl.Filter{}
+		filter.AddString("name", []string{c.pattern}, qfl.ComparasionLike)
+
+		builder := qfl.MongoBuilder{Filter: filter, CaseInsensitive: true}
+		doc, err := builder.Build()
+		assert.NoError(t, err)
+
+		condition := doc["name"].(map[string]any)
+		assert.Equal(t, c.regex, condition["$regex"], c.pattern)
+		assert.Equal(t, "is", condition["$options"], c.pattern)
+		assert.Regexp(t, regexp.MustCompile(c.regex), c.match, c.pattern)
+	}
+}
@@ -0,0 +1,198 @@
+package qfl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OpenSearchBuilder converts the rules of a filter into an OpenSearch (or
+// Elasticsearch) bool query, which is marshaled as the JSON of the query DSL.
+// Conditions are put in the filter context, so they don't affect the score.
+//
+// Keys are mapped to the fields of `Keys`, and keys that aren't in it are
+// skipped unless `Strict` is set. When `Keys` is nil, keys are used as the
+// name of their fields.
+type OpenSearchBuilder struct {
+	Filter          Filter
+	Keys            map[string]string
+	Strict          bool   // error on keys of the filter that aren't in Keys
+	TimeFormat      string // format of the time values, defaults to RFC3339 if empty
+	CaseInsensitive bool   // match LIKE patterns ignoring the case
+}
+
+// Build returns the query, which matches all documents when there are no
+// conditions.
+func (ob *OpenSearchBuilder) Build() (map[string]any, error) {
+	root, ok, err := ob.Filter.mappedExpression(ob.Keys, ob.Strict, "field")
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return map[string]any{"match_all": map[string]any{}}, nil
+	}
+
+	// The query is always a bool query, even for a single condition
+	if root.op == logicalLeaf {
+		root = ruleNode{op: logicalAnd, children: []ruleNode{root}}
+	}
+
+	query, _ := ob.buildNode(root)
+	return query, nil
+}
+
+// buildNode returns the query of the node, and if it must not match when it's
+// a negated condition.
+func (ob *OpenSearchBuilder) buildNode(node ruleNode) (map[string]any, bool) {
+	if node.op == logicalLeaf {
+		return ob.buildLeaf(node)
+	}
+
+	// Negated conditions of AND are put together on `must_not`, while the
+	// ones of OR need their own bool query
+	filter, mustNot, should := []any{}, []any{}, []any{}
+	for _, child := range ob.children(node, nil) {
+		query, negated := ob.buildNode(child)
+		switch {
+		case node.op == logicalOr && negated:
+			should = append(should, boolQuery("must_not", []any{query}))
+		case node.op == logicalOr:
+			should = append(should, query)
+		case negated:
+			mustNot = append(mustNot, query)
+		default:
+			filter = append(filter, query)
+		}
+	}
+
+	clauses := map[string]any{}
+	if len(filter) != 0 {
+		clauses["filter"] = filter
+	}
+	if len(mustNot) != 0 {
+		clauses["must_not"] = mustNot
+	}
+	if len(should) != 0 {
+		clauses["should"] = should
+		clauses["minimum_should_match"] = 1
+	}
+
+	return map[string]any{"bool": clauses}, false
+}
+
+// children returns the children of the node, replacing nested nodes with the
+// same operator by their own children.
+func (ob *OpenSearchBuilder) children(node ruleNode, children []ruleNode) []ruleNode {
+	for _, child := range node.children {
+		if child.op == node.op {
+			children = ob.children(child, children)
+		} else {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+func (ob *OpenSearchBuilder) buildLeaf(node ruleNode) (map[string]any, bool) {
+	key := ob.Filter.keys[node.key]
+	rule := key.rules[node.rule]
+	field, _ := mappedField(ob.Keys, key.key)
+
+	var values []any
+	switch key.Type {
+	case ruleTypeInt:
+		values = ruleValues(rule, ob.Filter.intVals)
+	case ruleTypeUint:
+		values = ruleValues(rule, ob.Filter.uintVals)
+	case ruleTypeFloat:
+		values = ruleValues(rule, ob.Filter.floatVals)
+	case ruleTypeString:
+		values = ruleValues(rule, ob.Filter.stringVals)
+	case ruleTypeTime:
+		format := ob.TimeFormat
+		if format == "" {
+			format = time.RFC3339
+		}
+
+		values = ruleValues(rule, ob.Filter.timeVals)
+		for i := range values {
+			values[i] = values[i].(time.Time).Format(format)
+		}
+	}
+
+	switch rule.Comparasion {
+	case ComparasionEquals, ComparasionNotEquals:
+		negated := rule.Comparasion == ComparasionNotEquals
+		if len(values) == 1 {
+			return map[string]any{"term": map[string]any{field: values[0]}}, negated
+		}
+
+		return map[string]any{"terms": map[string]any{field: values}}, negated
+	case ComparasionLessThan:
+		return rangeQuery(field, "lt", values[0]), false
+	case ComparasionMoreThan:
+		return rangeQuery(field, "gt", values[0]), false
+	case ComparasionLessOrEqual:
+		return rangeQuery(field, "lte", values[0]), false
+	case ComparasionMoreOrEqual:
+		return rangeQuery(field, "gte", values[0]), false
+	case ComparasionBetween:
+		return rangeQuery(field, "gte", values[0], "lte", values[1]), false
+	case ComparasionBetweenExclusive:
+		return rangeQuery(field, "gt", values[0], "lt", values[1]), false
+	case ComparasionLike, ComparasionNotLike:
+		return map[string]any{"wildcard": map[string]any{field: map[string]any{
+			"value":            likeToWildcard(fmt.Sprint(values[0])),
+			"case_insensitive": ob.CaseInsensitive,
+		}}}, rule.Comparasion == ComparasionNotLike
+	case ComparasionIsNull, ComparasionIsNotNull:
+		return map[string]any{"exists": map[string]any{"field": field}}, rule.Comparasion == ComparasionIsNull
+	}
+
+	return map[string]any{}, false
+}
+
+func boolQuery(clause string, queries []any) map[string]any {
+	return map[string]any{"bool": map[string]any{clause: queries}}
+}
+
+// rangeQuery returns a range query with the pairs of operator and value.
+func rangeQuery(field string, pairs ...any) map[string]any {
+	bounds := map[string]any{}
+	for i := 0; i < len(pairs); i += 2 {
+		bounds[pairs[i].(string)] = pairs[i+1]
+	}
+
+	return map[string]any{"range": map[string]any{field: bounds}}
+}
+
+// likeToWildcard converts a LIKE pattern into a wildcard pattern, where `%`
+// becomes `*`, `_` becomes `?` and the characters escaped by `\` are literals.
+func likeToWildcard(pattern string) string {
+	var sb strings.Builder
+
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped || (r != '\\' && r != '%' && r != '_'):
+			if r == '*' || r == '?' || r == '\\' {
+				sb.WriteRune('\\')
+			}
+			sb.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			sb.WriteRune('*')
+		case r == '_':
+			sb.WriteRune('?')
+		}
+	}
+
+	// A trailing backslash doesn't escape anything, so it's a literal
+	if escaped {
+		sb.WriteString(`\\`)
+	}
+
+	return sb.String()
+}
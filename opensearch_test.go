@@ -0,0 +1,103 @@
+package qfl_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleOpenSearchBuilder() {
+	filter := qfl.Filter{}
+	filter.AddString("name", []string{"Rob%"}, qfl.ComparasionLike)
+	filter.AddString("role", []string{"guest"}, qfl.ComparasionNotEquals)
+	filter.AddUint("age", []uint{18, 65}, qfl.ComparasionBetween)
+
+	builder := qfl.OpenSearchBuilder{
+		Filter: filter,
+		Keys:   map[string]string{"name": "name.keyword", "role": "role", "age": "age"},
+	}
+
+	query, err := builder.Build()
+	if err != nil {
+		// Treat error...
+	}
+
+	body, _ := json.Marshal(map[string]any{"query": query})
+	fmt.Println(string(body))
+	// Output:
+	// {"query":{"bool":{"filter":[{"wildcard":{"name.keyword":{"case_insensitive":false,"value":"Rob*"}}},{"range":{"age":{"gte":18,"lte":65}}}],"must_not":[{"term":{"role":"guest"}}]}}}
+}
+
+func TestOpenSearchBuilder(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddString("tag")
+	parser.AddTime("deleted_at")
+	parser.AddTime("created_at")
+
+	filter, err := parser.Parse(map[string]string{
+		"age":        "lt!18~gt!65|ne!70,80",
+		"tag":        "eq!a,b",
+		"deleted_at": "is!null",
+		"created_at": "bx!2024-01-01T00:00:00Z,2024-02-01T00:00:00Z",
+	})
+	assert.NoError(t, err)
+
+	builder := qfl.OpenSearchBuilder{Filter: *filter, TimeFormat: time.DateOnly}
+	query, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"bool": map[string]any{
+		"filter": []any{
+			map[string]any{"bool": map[string]any{
+				"should": []any{
+					map[string]any{"range": map[string]any{"age": map[string]any{"lt": 18}}},
+					map[string]any{"bool": map[string]any{
+						"filter":   []any{map[string]any{"range": map[string]any{"age": map[string]any{"gt": 65}}}},
+						"must_not": []any{map[string]any{"terms": map[string]any{"age": []any{70, 80}}}},
+					}},
+				},
+				"minimum_should_match": 1,
+			}},
+			map[string]any{"terms": map[string]any{"tag": []any{"a", "b"}}},
+			map[string]any{"range": map[string]any{"created_at": map[string]any{"gt": "2024-01-01", "lt": "2024-02-01"}}},
+		},
+		"must_not": []any{
+			map[string]any{"exists": map[string]any{"field": "deleted_at"}},
+		},
+	}}, query)
+}
+
+func TestOpenSearchBuilderSingle(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddString("name", []string{`50\%_a*b?`}, qfl.ComparasionNotLike)
+	filter.AddString("password", []string{"secret"}, qfl.ComparasionEquals)
+
+	builder := qfl.OpenSearchBuilder{
+		Filter:          filter,
+		Keys:            map[string]string{"name": "name"},
+		CaseInsensitive: true,
+	}
+
+	query, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"bool": map[string]any{
+		"must_not": []any{map[string]any{"wildcard": map[string]any{"name": map[string]any{
+			"value":            `50%?a\*b\?`,
+			"case_insensitive": true,
+		}}}},
+	}}, query)
+
+	builder.Strict = true
+	_, err = builder.Build()
+	assert.EqualError(t, err, "key `password` isn't mapped to a field")
+
+	builder = qfl.OpenSearchBuilder{}
+	query, err = builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"match_all": map[string]any{}}, query)
+}
@@ -0,0 +1,101 @@
+package qfl
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// KeyOption restricts what clients are allowed to use on a key of the parser.
+type KeyOption func(*keyOptions)
+
+type keyOptions struct {
+	comparators []ComparasionType
+	maxValues   int
+	maxRules    int
+	maxLength   int
+}
+
+// AllowComparators limits the comparators that can be used on the key. Note
+// that a single value without comparator uses `ComparasionEquals`.
+func AllowComparators(comparators ...ComparasionType) KeyOption {
+	return func(o *keyOptions) {
+		o.comparators = comparators
+	}
+}
+
+// MaxValues limits the amount of values a single rule of the key can have.
+func MaxValues(n int) KeyOption {
+	return func(o *keyOptions) {
+		o.maxValues = n
+	}
+}
+
+// MaxRules limits the amount of rules the key can have.
+func MaxRules(n int) KeyOption {
+	return func(o *keyOptions) {
+		o.maxRules = n
+	}
+}
+
+// MaxLength limits the amount of characters of each value of the key.
+func MaxLength(n int) KeyOption {
+	return func(o *keyOptions) {
+		o.maxLength = n
+	}
+}
+
+func newKeyOptions(opts []KeyOption) keyOptions {
+	o := keyOptions{}
+	for i := range opts {
+		opts[i](&o)
+	}
+
+	return o
+}
+
+// validate checks if the rules of the expression follow the options of their
+// keys, rules counts how many rules each key already have.
+func (p Parser) validate(expr ruleExpr, rules []int) *ParseError {
+	if expr.op != logicalLeaf {
+		for i := range expr.children {
+			if err := p.validate(expr.children[i], rules); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	opts := p.options[expr.key]
+	if opts.comparators != nil && !slices.Contains(opts.comparators, expr.comparasion) {
+		expected := make([]string, len(opts.comparators))
+		for i := range opts.comparators {
+			expected[i] = opts.comparators[i].identifier()
+		}
+
+		return newParseError(ParseErrorComparatorNotAllowed, expr.tok, expected...)
+	}
+
+	rules[expr.key] += 1
+	if opts.maxRules > 0 && rules[expr.key] > opts.maxRules {
+		err := newParseError(ParseErrorTooManyRules, expr.tok)
+		err.Limit = opts.maxRules
+		return err
+	}
+
+	if opts.maxValues > 0 && len(expr.values) > opts.maxValues {
+		err := newParseError(ParseErrorTooManyValues, expr.values[opts.maxValues])
+		err.Limit = opts.maxValues
+		return err
+	}
+
+	for i := range expr.values {
+		if opts.maxLength > 0 && utf8.RuneCountInString(expr.values[i].Value) > opts.maxLength {
+			err := newParseError(ParseErrorValueTooLong, expr.values[i])
+			err.Limit = opts.maxLength
+			return err
+		}
+	}
+
+	return nil
+}
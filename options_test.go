@@ -0,0 +1,67 @@
+package qfl_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleAllowComparators() {
+	parser := qfl.Parser{}
+	parser.AddString("name", qfl.AllowComparators(qfl.ComparasionEquals), qfl.MaxValues(2))
+
+	_, err := parser.Parse(map[string]string{"name": "lk!%John%"})
+	fmt.Println(err)
+
+	_, err = parser.Parse(map[string]string{"name": "eq!John,Mary,Bob"})
+	fmt.Println(err)
+	// Output:
+	// key `name` at offset 0: comparator `lk` is not allowed, expected `eq`
+	// key `name` at offset 13: too many values, the limit is 2
+}
+
+func TestParserKeyOptions(t *testing.T) {
+	parser := qfl.Parser{ExpressionKey: "filter"}
+	parser.AddInt("age", qfl.AllowComparators(qfl.ComparasionMoreThan, qfl.ComparasionLessThan), qfl.MaxRules(2))
+	parser.AddString("name", qfl.MaxLength(5), qfl.MaxValues(3))
+
+	cases := []struct {
+		key    string
+		value  string
+		kind   qfl.ParseErrorKind
+		offset int
+		limit  int
+	}{
+		{"age", "20", qfl.ParseErrorComparatorNotAllowed, 0, 0},
+		{"age", "gt!20|le!30", qfl.ParseErrorComparatorNotAllowed, 6, 0},
+		{"age", "gt!20|lt!30~gt!40", qfl.ParseErrorTooManyRules, 12, 2},
+		{"name", "eq!a,b,c,d", qfl.ParseErrorTooManyValues, 9, 3},
+		{"name", "eq!a,abcdef", qfl.ParseErrorValueTooLong, 5, 5},
+		{"filter", "age:gt!1 or age:lt!2 or age:gt!3", qfl.ParseErrorTooManyRules, 28, 2},
+	}
+
+	for _, c := range cases {
+		_, err := parser.Parse(map[string]string{c.key: c.value})
+
+		var pe *qfl.ParseError
+		if assert.True(t, errors.As(err, &pe), c.value) {
+			assert.Equal(t, c.key, pe.Key, c.value)
+			assert.Equal(t, c.kind, pe.Kind, c.value)
+			assert.Equal(t, c.offset, pe.Offset, c.value)
+			assert.Equal(t, c.limit, pe.Limit, c.value)
+		}
+	}
+
+	// Values without comparator report the implicit `eq`
+	_, err := parser.Parse(map[string]string{"age": "20"})
+	assert.EqualError(t, err, "key `age` at offset 0: comparator `eq` is not allowed, expected `gt` or `lt`")
+
+	fm, err := parser.Parse(map[string]string{"age": "gt!20|lt!30", "name": "eq!John,Mary"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(fm.GetInt("age")))
+	assert.Equal(t, 1, len(fm.GetString("name")))
+}
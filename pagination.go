@@ -0,0 +1,107 @@
+package qfl
+
+import (
+	"math"
+	"strconv"
+)
+
+// Pagination describes the variables that select the page of the results and
+// the limits they must respect.
+type Pagination struct {
+	LimitKey    string // variable with the size of the page, defaults to `limit`
+	PageKey     string // variable with the zero-indexed page, defaults to `page`
+	DefaultSize uint64 // size of the page when it's not given, MaxSize if zero or bigger
+	MaxSize     uint64 // maximum size of the page, unlimited if zero
+	MaxOffset   uint64 // maximum amount of rows skipped by the page, unlimited if zero
+}
+
+// Page is the slice of the sorted results that should be fetched.
+type Page struct {
+	Limit  uint64 // maximum amount of rows
+	Number uint64 // zero-indexed number of the page
+}
+
+// Offset returns the amount of rows before the page.
+func (p Page) Offset() uint64 {
+	return p.Limit * p.Number
+}
+
+// SetPage sets the page of the results to fetch, nil fetches all of them.
+func (f *Filter) SetPage(page *Page) {
+	f.page = page
+}
+
+// GetPage returns the page of the results to fetch, or nil if all of them
+// should be fetched.
+func (f *Filter) GetPage() *Page {
+	return f.page
+}
+
+// parsePage parses the variables of the pagination. When the size isn't given
+// and there's no default nor maximum size, there's no page and the page
+// variable is rejected, as it would be silently ignored.
+func (pg Pagination) parsePage(kv map[string]string) (*Page, *ParseError) {
+	limitKey, pageKey := pg.LimitKey, pg.PageKey
+	if limitKey == "" {
+		limitKey = "limit"
+	}
+	if pageKey == "" {
+		pageKey = "page"
+	}
+
+	// Clients can't skip the maximum size by not giving one
+	page := &Page{Limit: pg.DefaultSize}
+	if page.Limit == 0 || (pg.MaxSize != 0 && page.Limit > pg.MaxSize) {
+		page.Limit = pg.MaxSize
+	}
+
+	if value, ok := kv[limitKey]; ok {
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || limit == 0 {
+			err := newParseError(ParseErrorInvalidValue, token{Value: value}, "size")
+			err.Key = limitKey
+			return nil, err
+		} else if pg.MaxSize != 0 && limit > pg.MaxSize {
+			err := newParseError(ParseErrorOutOfRange, token{Value: value})
+			err.Key = limitKey
+			err.Limit = int(min(pg.MaxSize, math.MaxInt))
+			return nil, err
+		}
+
+		page.Limit = limit
+	}
+
+	value, ok := kv[pageKey]
+	if page.Limit == 0 && ok {
+		err := newParseError(ParseErrorUnexpectedToken, token{}, "size")
+		err.Key = limitKey
+		return nil, err
+	} else if page.Limit == 0 {
+		return nil, nil
+	} else if !ok {
+		return page, nil
+	}
+
+	number, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		err := newParseError(ParseErrorInvalidValue, token{Value: value}, "page")
+		err.Key = pageKey
+		return nil, err
+	}
+
+	// The offset can't be bigger than the maximum, nor overflow
+	maxOffset := uint64(math.MaxUint64)
+	if pg.MaxOffset != 0 {
+		maxOffset = pg.MaxOffset
+	}
+
+	if number > maxOffset/page.Limit {
+		err := newParseError(ParseErrorOutOfRange, token{Value: value})
+		err.Key = pageKey
+		err.Limit = int(min(maxOffset/page.Limit, math.MaxInt))
+		return nil, err
+	}
+
+	page.Number = number
+	return page, nil
+}
@@ -0,0 +1,130 @@
+package qfl_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExamplePagination() {
+	parser := qfl.Parser{
+		Pagination: &qfl.Pagination{DefaultSize: 20, MaxSize: 100, MaxOffset: 10000},
+	}
+	parser.AddInt("age")
+
+	u, _ := url.Parse("https://example.com/users?age=gt!20&limit=50&page=3")
+	filter, err := parser.ParseURL(u)
+	if err != nil {
+		// Treat error...
+	}
+
+	builder := qfl.SQLBuilder{
+		Filter:  *filter,
+		Keys:    map[string]string{"age": "age"},
+		Dialect: qfl.PostgresDialect{},
+	}
+
+	builder.Select("users", "id")
+	builder.Where()
+	builder.Paginate()
+
+	fmt.Println(builder.Builder.String())
+
+	_, err = parser.Parse(map[string]string{"limit": "500"})
+	fmt.Println(err)
+	// Output:
+	// SELECT id FROM users
+	// WHERE age > $1
+	// LIMIT 50 OFFSET 150
+	//
+	// key `limit` at offset 0: value `500` is out of range, the limit is 100
+}
+
+func TestParserPagination(t *testing.T) {
+	parser := qfl.Parser{
+		Pagination: &qfl.Pagination{LimitKey: "size", PageKey: "p", DefaultSize: 10, MaxSize: 50, MaxOffset: 1000},
+	}
+
+	fm, err := parser.Parse(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, &qfl.Page{Limit: 10}, fm.GetPage())
+
+	fm, err = parser.Parse(map[string]string{"size": "50", "p": "20"})
+	assert.NoError(t, err)
+	assert.Equal(t, &qfl.Page{Limit: 50, Number: 20}, fm.GetPage())
+	assert.Equal(t, uint64(1000), fm.GetPage().Offset())
+
+	cases := []struct {
+		kv    map[string]string
+		key   string
+		kind  qfl.ParseErrorKind
+		limit int
+	}{
+		{map[string]string{"size": "0"}, "size", qfl.ParseErrorInvalidValue, 0},
+		{map[string]string{"size": "-1"}, "size", qfl.ParseErrorInvalidValue, 0},
+		{map[string]string{"size": "51"}, "size", qfl.ParseErrorOutOfRange, 50},
+		{map[string]string{"p": "x"}, "p", qfl.ParseErrorInvalidValue, 0},
+		{map[string]string{"size": "50", "p": "21"}, "p", qfl.ParseErrorOutOfRange, 20},
+	}
+
+	for _, c := range cases {
+		_, err := parser.Parse(c.kv)
+
+		var pe *qfl.ParseError
+		if assert.True(t, errors.As(err, &pe), c.kv) {
+			assert.Equal(t, c.key, pe.Key, c.kv)
+			assert.Equal(t, c.kind, pe.Kind, c.kv)
+			assert.Equal(t, c.limit, pe.Limit, c.kv)
+		}
+	}
+}
+
+func TestParserPaginationMaxSize(t *testing.T) {
+	parser := qfl.Parser{Pagination: &qfl.Pagination{MaxSize: 100}}
+
+	fm, err := parser.Parse(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, &qfl.Page{Limit: 100}, fm.GetPage())
+
+	fm, err = parser.Parse(map[string]string{"page": "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, &qfl.Page{Limit: 100, Number: 3}, fm.GetPage())
+
+	// Default sizes bigger than the maximum are capped by it
+	parser.Pagination.DefaultSize = 500
+	fm, err = parser.Parse(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, &qfl.Page{Limit: 100}, fm.GetPage())
+}
+
+func TestParserPaginationOverflow(t *testing.T) {
+	parser := qfl.Parser{Pagination: &qfl.Pagination{}}
+
+	fm, err := parser.Parse(map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, fm.GetPage())
+
+	// The page can't be selected without a size
+	_, err = parser.Parse(map[string]string{"page": "2"})
+	assert.EqualError(t, err, "key `limit` at offset 0: expected `size`, got end of value")
+
+	_, err = parser.Parse(map[string]string{"limit": "1000", "page": fmt.Sprint(uint64(math.MaxUint64 / 100))})
+	var pe *qfl.ParseError
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, qfl.ParseErrorOutOfRange, pe.Kind)
+	}
+
+	filter := qfl.Filter{}
+	filter.SetPage(&qfl.Page{Limit: 10, Number: 2})
+
+	query := qfl.SQLSelect{Filter: filter, Dialect: qfl.OracleDialect{}}
+	sql, _, err := query.Select("users").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users\nOFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", sql)
+}
@@ -1,42 +1,59 @@
 package qfl
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Parser parses the QFL language for the keys you specify
 type Parser struct {
-	TimeFormat string // defaults to RCF3339 if empty
-	keys       []string
-	types      []ruleType
+	TimeFormat    string      // defaults to RCF3339 if empty
+	ExpressionKey string      // variable with an expression across keys, disabled if empty
+	CollectErrors bool        // keep parsing the other keys when one of them is invalid
+	SortKey       string      // variable with the fields to sort by, disabled if empty
+	CursorKey     string      // variable with the cursor of keyset pagination, disabled if empty
+	CursorSecret  []byte      // key used to sign the cursors, required by CursorKey
+	Pagination    *Pagination // variables of the page and their limits, disabled if nil
+	FieldsKey     string      // variable with the fields to select, disabled if empty
+	keys          []string
+	types         []ruleType
+	options       []keyOptions
+	sortKeys      []string
+	fieldKeys     []string
 }
 
-func (p *Parser) AddInt(key string) {
+func (p *Parser) AddInt(key string, opts ...KeyOption) {
 	p.keys = append(p.keys, key)
 	p.types = append(p.types, ruleTypeInt)
+	p.options = append(p.options, newKeyOptions(opts))
 }
 
-func (p *Parser) AddUint(key string) {
+func (p *Parser) AddUint(key string, opts ...KeyOption) {
 	p.keys = append(p.keys, key)
 	p.types = append(p.types, ruleTypeUint)
+	p.options = append(p.options, newKeyOptions(opts))
 }
 
-func (p *Parser) AddFloat(key string) {
+func (p *Parser) AddFloat(key string, opts ...KeyOption) {
 	p.keys = append(p.keys, key)
 	p.types = append(p.types, ruleTypeFloat)
+	p.options = append(p.options, newKeyOptions(opts))
 }
 
-func (p *Parser) AddString(key string) {
+func (p *Parser) AddString(key string, opts ...KeyOption) {
 	p.keys = append(p.keys, key)
 	p.types = append(p.types, ruleTypeString)
+	p.options = append(p.options, newKeyOptions(opts))
 }
 
-func (p *Parser) AddTime(key string) {
+func (p *Parser) AddTime(key string, opts ...KeyOption) {
 	p.keys = append(p.keys, key)
 	p.types = append(p.types, ruleTypeTime)
+	p.options = append(p.options, newKeyOptions(opts))
 }
 
 // ParseURL reads query variables and returns the filter containing all rules
@@ -51,229 +68,462 @@ func (p Parser) ParseURL(u *url.URL) (*Filter, error) {
 	return p.Parse(kv)
 }
 
+// Parse reads the variables of the map and returns the filter containing all
+// rules for them. Invalid values are reported as a `*ParseError`.
+//
+// When `CollectErrors` is set, it returns the filter with the rules of the
+// valid keys, along with the errors of all invalid ones joined together.
 func (p Parser) Parse(kv map[string]string) (*Filter, error) {
 	// Ensure that time format is set before running the parser
 	if p.TimeFormat == "" {
 		p.TimeFormat = time.RFC3339
 	}
 
+	if p.CursorKey != "" && len(p.CursorSecret) == 0 {
+		return nil, fmt.Errorf("field `CursorSecret` is empty")
+	}
+
 	fm := &Filter{}
+	nodes := []ruleNode{}
+	errs := []error{}
+	rules := make([]int, len(p.keys))
 	for i := range p.keys {
 		if _, ok := kv[p.keys[i]]; !ok {
 			continue
 		}
 
-		tokens := p.tokenize(kv[p.keys[i]])
-		if len(tokens) == 1 {
-			switch p.types[i] {
-			case ruleTypeFloat:
-				val, err := strconv.ParseFloat(tokens[0].Value, 64)
-				if err != nil {
-					return nil, fmt.Errorf("value `%s` is an invalid float", tokens[0].Value)
-				}
-				fm.AddFloat(p.keys[i], []float64{val}, ComparasionEquals)
-
-			case ruleTypeInt:
-				val, err := strconv.ParseInt(tokens[0].Value, 10, 0)
-				if err != nil {
-					return nil, fmt.Errorf("value `%s` is an invalid int", tokens[0].Value)
-				}
-				fm.AddInt(p.keys[i], []int{int(val)}, ComparasionEquals)
-
-			case ruleTypeString:
-				fm.AddString(p.keys[i], []string{tokens[0].Value}, ComparasionEquals)
-
-			case ruleTypeTime:
-				t, err := time.Parse(p.TimeFormat, tokens[0].Value)
-				if err != nil {
-					return nil, fmt.Errorf("value `%s` is not a time formatted as `%s`", tokens[0].Value, p.TimeFormat)
-				}
-				fm.AddTime(p.keys[i], []time.Time{t}, ComparasionEquals)
-
-			case ruleTypeUint:
-				val, err := strconv.ParseUint(tokens[0].Value, 10, 0)
-				if err != nil {
-					return nil, fmt.Errorf("value `%s` is an invalid uint", tokens[0].Value)
-				}
-				fm.AddUint(p.keys[i], []uint{uint(val)}, ComparasionEquals)
-
-			default:
-				return nil, fmt.Errorf("unexpected pkg.RuleType: %#v", p.types[i])
-			}
+		node, err := p.parseKey(fm, i, kv[p.keys[i]], rules)
+		if err != nil && !p.CollectErrors {
+			return nil, err
+		} else if err != nil {
+			errs = append(errs, err)
 			continue
 		}
 
-		lastState := tokens[0].Type
-		comparasion := tokens[0].comparasionType()
-		valsIdx := []int{}
+		nodes = append(nodes, node)
+	}
+
+	if expression, ok := kv[p.ExpressionKey]; ok && p.ExpressionKey != "" {
+		expr, err := p.parseExpression(expression)
+		if err == nil {
+			err = p.validate(expr, rules)
+		}
+
+		if err == nil {
+			err = p.parseValues(&expr)
+		}
+
+		if err != nil {
+			err.Key = p.ExpressionKey
+			if !p.CollectErrors {
+				return nil, err
+			}
 
-		if lastState != tokenIdentifier {
-			return nil, fmt.Errorf("expected comparator, found `%s`", tokens[0].Value)
+			errs = append(errs, err)
+		} else {
+			nodes = append(nodes, expr.commit(fm, p))
 		}
+	}
 
-		if comparasion == ComparasionInvalid {
-			return nil, fmt.Errorf("expected valid comparator, got `%s`", tokens[0].Value)
+	if value, ok := kv[p.FieldsKey]; ok && p.FieldsKey != "" {
+		fields, err := p.parseFields(value)
+		if err != nil {
+			err.Key = p.FieldsKey
+			if !p.CollectErrors {
+				return nil, err
+			}
+
+			errs = append(errs, err)
+		} else {
+			fm.fields = fields
 		}
+	}
 
-		tokens = append(tokens, token{Type: tokenEnd, Value: ""})
-		for j := 1; j < len(tokens); j++ {
-			switch tokens[j].Type {
-			case tokenMark:
-				if lastState != tokenIdentifier {
-					return nil, fmt.Errorf("expected comparator, got `%s`", tokens[j-1].Value)
-				}
-			case tokenComma:
-				if lastState != tokenValue {
-					return nil, fmt.Errorf("expected value, got `%s`", tokens[j-1].Value)
-				} else if comparasion != ComparasionEquals {
-					return nil, fmt.Errorf("comma is only supported on `eq` comparator")
-				}
-
-			case tokenIdentifier:
-				if lastState != tokenBar {
-					return nil, fmt.Errorf("expected `!`, got `%s`", tokens[j].Value)
-				}
-
-				comparasion = tokens[j].comparasionType()
-			case tokenValue:
-				if lastState != tokenMark && lastState != tokenComma {
-					return nil, fmt.Errorf("expected `|` or comma, got `%s`", tokens[j].Value)
-				}
-
-				valsIdx = append(valsIdx, j)
-			case tokenBar, tokenEnd:
-				if lastState != tokenValue {
-					return nil, fmt.Errorf("expected ``, got `%s`", tokens[j].Value)
-				}
-
-				switch p.types[i] {
-				case ruleTypeFloat:
-					floats := make([]float64, len(valsIdx))
-					for k := range valsIdx {
-						val, err := strconv.ParseFloat(tokens[valsIdx[k]].Value, 64)
-						if err != nil {
-							return nil, fmt.Errorf("value `%s` is an invalid float", tokens[valsIdx[k]].Value)
-						}
-
-						floats[k] = val
-					}
-
-					fm.AddFloat(p.keys[i], floats, comparasion)
-				case ruleTypeInt:
-					ints := make([]int, len(valsIdx))
-					for k := range valsIdx {
-						val, err := strconv.ParseInt(tokens[valsIdx[k]].Value, 10, 0)
-						if err != nil {
-							return nil, fmt.Errorf("value `%s` is an invalid int", tokens[valsIdx[k]].Value)
-						}
-
-						ints[k] = int(val)
-					}
-
-					fm.AddInt(p.keys[i], ints, comparasion)
-				case ruleTypeString:
-					strings := make([]string, len(valsIdx))
-					for k := range valsIdx {
-						strings[k] = tokens[valsIdx[k]].Value
-					}
-
-					fm.AddString(p.keys[i], strings, comparasion)
-				case ruleTypeTime:
-					times := make([]time.Time, len(valsIdx))
-					for k := range valsIdx {
-						t, err := time.Parse(p.TimeFormat, tokens[valsIdx[k]].Value)
-						if err != nil {
-							return nil, fmt.Errorf("value `%s` is not a time formatted as `%s`", tokens[valsIdx[k]].Value, p.TimeFormat)
-						}
-
-						times[k] = t
-					}
-
-					fm.AddTime(p.keys[i], times, comparasion)
-				case ruleTypeUint:
-					uints := make([]uint, len(valsIdx))
-					for k := range valsIdx {
-						val, err := strconv.ParseUint(tokens[valsIdx[k]].Value, 10, 0)
-						if err != nil {
-							return nil, fmt.Errorf("value `%s` is an invalid uint", tokens[valsIdx[k]].Value)
-						}
-
-						uints[k] = uint(val)
-					}
-
-					fm.AddUint(p.keys[i], uints, comparasion)
-				default:
-					return nil, fmt.Errorf("unexpected pkg.RuleType: %#v", p.types[i])
-				}
-
-				// Resize to 0
-				valsIdx = valsIdx[:0]
+	if p.Pagination != nil {
+		page, err := p.Pagination.parsePage(kv)
+		if err != nil && !p.CollectErrors {
+			return nil, err
+		} else if err != nil {
+			errs = append(errs, err)
+		} else {
+			fm.page = page
+		}
+	}
+
+	if value, ok := kv[p.SortKey]; ok && p.SortKey != "" {
+		fields, err := p.parseSort(value)
+		if err != nil {
+			err.Key = p.SortKey
+			if !p.CollectErrors {
+				return nil, err
 			}
-			lastState = tokens[j].Type
+
+			errs = append(errs, err)
+		} else {
+			fm.sort = fields
 		}
 	}
 
-	return fm, nil
+	// Cursors are only valid for the sort they were made with
+	if value, ok := kv[p.CursorKey]; ok && p.CursorKey != "" {
+		cursor, err := p.parseCursor(value, fm.sort)
+		if err != nil {
+			err.Key = p.CursorKey
+			if !p.CollectErrors {
+				return nil, err
+			}
+
+			errs = append(errs, err)
+		} else {
+			fm.cursor = cursor
+		}
+	}
+
+	root := ruleNode{op: logicalAnd, children: nodes}
+	if root.hasOr() {
+		fm.expr = &root
+	}
+
+	return fm, errors.Join(errs...)
 }
 
-// Tokenize a string using a sliding window to work on that slice as it expands
-// until it matches one of the identifier/symbols or as a value when it ends on
-// either `,` or `|`, unless if either is escaped with `\`.
+// parseKey parses the value for the i-th key and commits its rules into the
+// filter.
+func (p Parser) parseKey(fm *Filter, i int, value string, rules []int) (ruleNode, *ParseError) {
+	tokens := p.tokenize(value)
+	expr, err := parseTokens(tokens)
+	if err == nil {
+		expr.setKey(i)
+		err = p.validate(expr, rules)
+	}
+
+	if err == nil {
+		err = p.parseValues(&expr)
+	}
+
+	// Rules are only committed if the whole value is valid
+	if err != nil {
+		err.Key = p.keys[i]
+		return ruleNode{}, err
+	}
+
+	return expr.commit(fm, p), nil
+}
+
+// parseTokens builds the expression tree for the value of a key. A single
+// value is a shorthand for the `eq` comparator, otherwise the value follows
+// the grammar below, where AND (`|`) has precedence over OR (`~`):
+//
+//	or   = and { "~" and }
+//	and  = term { "|" term }
+//	term = "(" or ")" | comparator "!" value { "," value }
+func parseTokens(tokens []token) (ruleExpr, *ParseError) {
+	if len(tokens) == 2 && tokens[0].Type == tokenValue {
+		// The comparator is implicit, so it's reported at the start of the value
+		tok := token{Type: tokenIdentifier, Value: "eq", Offset: tokens[0].Offset}
+		return ruleExpr{comparasion: ComparasionEquals, tok: tok, values: tokens[:1]}, nil
+	}
+
+	ps := tokenParser{tokens: tokens}
+	expr, err := ps.parseOr()
+	if err != nil {
+		return expr, err
+	}
+
+	if tok := ps.peek(); tok.Type != tokenEnd {
+		return expr, newParseError(ParseErrorUnexpectedToken, tok, "|", "~", "end")
+	}
+
+	return expr, nil
+}
+
+type tokenParser struct {
+	tokens []token
+	pos    int
+}
+
+func (ps *tokenParser) peek() token {
+	return ps.tokens[ps.pos]
+}
+
+func (ps *tokenParser) next() token {
+	tok := ps.tokens[ps.pos]
+	if tok.Type != tokenEnd {
+		ps.pos += 1
+	}
+
+	return tok
+}
+
+func (ps *tokenParser) parseOr() (ruleExpr, *ParseError) {
+	return ps.parseList(logicalOr, tokenOr, ps.parseAnd)
+}
+
+func (ps *tokenParser) parseAnd() (ruleExpr, *ParseError) {
+	return ps.parseList(logicalAnd, tokenBar, ps.parseTerm)
+}
+
+// parseList parses a list of operands separated by the given token.
+func (ps *tokenParser) parseList(op logicalOp, separator tokenType, operand func() (ruleExpr, *ParseError)) (ruleExpr, *ParseError) {
+	first, err := operand()
+	if err != nil {
+		return first, err
+	}
+
+	expr := ruleExpr{op: op, children: []ruleExpr{first}}
+	for ps.peek().Type == separator {
+		ps.next()
+
+		child, err := operand()
+		if err != nil {
+			return expr, err
+		}
+
+		expr.children = append(expr.children, child)
+	}
+
+	if len(expr.children) == 1 {
+		return first, nil
+	}
+
+	return expr, nil
+}
+
+func (ps *tokenParser) parseTerm() (ruleExpr, *ParseError) {
+	tok := ps.next()
+	if tok.Type == tokenOpen {
+		expr, err := ps.parseOr()
+		if err != nil {
+			return expr, err
+		}
+
+		if tok := ps.next(); tok.Type != tokenClose {
+			return expr, newParseError(ParseErrorUnexpectedToken, tok, "|", "~", ")")
+		}
+
+		return expr, nil
+	}
+
+	if tok.Type != tokenIdentifier {
+		// Values that look like a rule most likely have a typo on the comparator
+		if mark := strings.IndexByte(tok.Value, '!'); tok.Type == tokenValue && mark > 0 {
+			tok.Value = tok.Value[:mark]
+			return ruleExpr{}, newParseError(ParseErrorInvalidComparator, tok)
+		}
+
+		return ruleExpr{}, newParseError(ParseErrorUnexpectedToken, tok, "comparator", "(")
+	}
+
+	expr := ruleExpr{comparasion: tok.comparasionType(), tok: tok}
+	if tok := ps.next(); tok.Type != tokenMark {
+		return expr, newParseError(ParseErrorUnexpectedToken, tok, "!")
+	}
+
+	// Presence checks doesn't have values, only the kind of check
+	if expr.comparasion == ComparasionIsNull {
+		switch tok := ps.next(); tok.Value {
+		case "null":
+		case "notnull":
+			expr.comparasion = ComparasionIsNotNull
+		default:
+			return expr, newParseError(ParseErrorUnexpectedToken, tok, "null", "notnull")
+		}
+
+		return expr, nil
+	}
+
+	for {
+		tok := ps.next()
+		if tok.Type != tokenValue {
+			return expr, newParseError(ParseErrorUnexpectedToken, tok, "value")
+		}
+		expr.values = append(expr.values, tok)
+
+		// Ranges always have two values, while lists can have any amount
+		next := ps.peek()
+		if expr.comparasion.isRange() && len(expr.values) == 1 && next.Type != tokenComma {
+			return expr, newParseError(ParseErrorUnexpectedToken, next, ",")
+		} else if next.Type != tokenComma {
+			break
+		} else if !expr.comparasion.isList() || (expr.comparasion.isRange() && len(expr.values) == 2) {
+			return expr, newParseError(ParseErrorUnexpectedToken, next, "|", "~", "end")
+		}
+		ps.next()
+	}
+
+	return expr, nil
+}
+
+// ruleExpr is the expression tree parsed from the query, the leaves are rules
+// for one of the parser keys with the values that still needs to be committed
+// into a filter.
+type ruleExpr struct {
+	op          logicalOp
+	key         int
+	tok         token
+	comparasion ComparasionType
+	values      []token
+	parsed      any
+	children    []ruleExpr
+}
+
+// setKey sets the index of the parser key for all the leaves.
+func (expr *ruleExpr) setKey(key int) {
+	expr.key = key
+	for i := range expr.children {
+		expr.children[i].setKey(key)
+	}
+}
+
+// parseValues converts the values of all rules into the type of their key.
+func (p Parser) parseValues(expr *ruleExpr) *ParseError {
+	if expr.op != logicalLeaf {
+		for i := range expr.children {
+			if err := p.parseValues(&expr.children[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var err *ParseError
+	switch ruleType := p.types[expr.key]; ruleType {
+	case ruleTypeFloat:
+		expr.parsed, err = parseGeneric(expr, []string{"float"}, func(v string) (float64, error) {
+			return strconv.ParseFloat(v, 64)
+		})
+	case ruleTypeInt:
+		expr.parsed, err = parseGeneric(expr, []string{"int"}, func(v string) (int, error) {
+			val, err := strconv.ParseInt(v, 10, 0)
+			return int(val), err
+		})
+	case ruleTypeString:
+		expr.parsed, err = parseGeneric(expr, []string{"string"}, func(v string) (string, error) {
+			return v, nil
+		})
+	case ruleTypeTime:
+		expr.parsed, err = parseGeneric(expr, []string{"time", p.TimeFormat}, func(v string) (time.Time, error) {
+			return time.Parse(p.TimeFormat, v)
+		})
+	case ruleTypeUint:
+		expr.parsed, err = parseGeneric(expr, []string{"uint"}, func(v string) (uint, error) {
+			val, err := strconv.ParseUint(v, 10, 0)
+			return uint(val), err
+		})
+	default:
+		panic(fmt.Sprintf("unexpected pkg.RuleType: %#v", ruleType))
+	}
+
+	return err
+}
+
+func parseGeneric[T Primitive](expr *ruleExpr, expected []string, parse func(string) (T, error)) ([]T, *ParseError) {
+	tokens := expr.values
+	values := make([]T, len(tokens))
+	for i := range tokens {
+		val, err := parse(tokens[i].Value)
+		if err != nil {
+			return nil, newParseError(ParseErrorInvalidValue, tokens[i], expected...)
+		}
+
+		values[i] = val
+	}
+
+	// Strings aren't validated since their order depends on the collation
+	if _, isString := any(values).([]string); expr.comparasion.isRange() && !isString {
+		if compare(values[0], values[1]) > 0 {
+			return nil, newParseError(ParseErrorInvalidRange, tokens[0])
+		}
+	}
+
+	return values, nil
+}
+
+// commit adds the rules into the filter and returns the equivalent node.
+func (expr ruleExpr) commit(fm *Filter, p Parser) ruleNode {
+	if expr.op == logicalLeaf {
+		return fm.addRule(p.keys[expr.key], p.types[expr.key], expr.parsed, expr.comparasion)
+	}
+
+	node := ruleNode{op: expr.op}
+	for i := range expr.children {
+		child := expr.children[i].commit(fm, p)
+
+		// Flatten nested nodes with the same operator
+		if child.op == node.op {
+			node.children = append(node.children, child.children...)
+		} else {
+			node.children = append(node.children, child)
+		}
+	}
+
+	return node
+}
+
+// Tokenize a string by walking through it until it matches one of the
+// identifier/symbols at the start of a slice, or as a value when it ends on
+// either `,`, `|`, `~` or `)`, unless if either is escaped with `\`.
+//
+// To keep single values unambiguous, `(` is only a symbol at the start of a
+// rule, while `~` and `)` only ends a value after the comparator, and `)` only
+// when a group is open. Strings without an unescaped `!` don't have
+// comparators, so they are never symbols on them.
 func (p Parser) tokenize(str string) (tokens []token) {
-	var slice string
-	il, ih := 0, 1
+	il := 0
 	afterMark := false
-	escapeNest := 0
-	for ih <= len(str) {
-		slice = str[il:ih]
-		switch slice {
-		case "!":
+	escaped := false
+	grouping := hasMark(str)
+	depth := 0 // groups that are open
+	for ih := 0; ih < len(str); ih++ {
+		if escaped {
+			escaped = false
+			continue
+		}
+
+		atStart := il == ih
+		switch c := str[ih]; {
+		case c == '\\':
+			escaped = true
+		case atStart && c == '!':
 			afterMark = true
-			tokens = append(tokens, token{Type: tokenMark, Value: "!"})
-			il = ih
-		case "lt", "gt", "le", "ge", "lk", "eq":
-			if !afterMark {
-				tokens = append(tokens, token{Type: tokenIdentifier, Value: slice})
-				il = ih
-			}
-		default:
-			last := len(slice) - 1
-			lastChar := slice[last]
-
-			if escapeNest%2 == 0 {
-				switch lastChar {
-				case '|':
-					afterMark = false
-					if len(slice) > 1 {
-						slice = slice[:last]
-						tokens = append(tokens, token{Type: tokenValue, Value: slice})
-					}
-					tokens = append(tokens, token{Type: tokenBar, Value: "|"})
-					il = ih
-				case ',':
-					if len(slice) > 1 {
-						slice = slice[:last]
-						tokens = append(tokens, token{Type: tokenValue, Value: slice})
-					}
-					tokens = append(tokens, token{Type: tokenComma, Value: ","})
-
-					il = ih
-				}
+			tokens = append(tokens, token{Type: tokenMark, Value: "!", Offset: ih})
+			il = ih + 1
+		case grouping && atStart && !afterMark && c == '(':
+			tokens = append(tokens, token{Type: tokenOpen, Value: "(", Offset: ih})
+			depth++
+			il = ih + 1
+		case atStart && !afterMark && isComparator(str[ih:]):
+			tokens = append(tokens, token{Type: tokenIdentifier, Value: str[ih : ih+2], Offset: ih})
+			ih += 1
+			il = ih + 1
+		case c == '|', c == ',', grouping && (afterMark || atStart) && (c == '~' || (c == ')' && depth > 0)):
+			if !atStart {
+				tokens = append(tokens, token{Type: tokenValue, Value: str[il:ih], Offset: il})
 			}
 
-			if lastChar == '\\' {
-				escapeNest += 1
-			} else {
-				escapeNest = 0
+			switch c {
+			case '|':
+				afterMark = false
+				tokens = append(tokens, token{Type: tokenBar, Value: "|", Offset: ih})
+			case '~':
+				afterMark = false
+				tokens = append(tokens, token{Type: tokenOr, Value: "~", Offset: ih})
+			case ')':
+				afterMark = false
+				depth--
+				tokens = append(tokens, token{Type: tokenClose, Value: ")", Offset: ih})
+			case ',':
+				tokens = append(tokens, token{Type: tokenComma, Value: ",", Offset: ih})
 			}
+			il = ih + 1
 		}
-
-		ih += 1
 	}
 
-	if len(slice) > 0 {
-		tokens = append(tokens, token{Type: tokenValue, Value: slice})
+	if il < len(str) {
+		tokens = append(tokens, token{Type: tokenValue, Value: str[il:], Offset: il})
 	}
+	tokens = append(tokens, token{Type: tokenEnd, Value: "", Offset: len(str)})
 
 	// Do another pass to remove the escaped symbol
 	for i := range tokens {
@@ -283,9 +533,28 @@ func (p Parser) tokenize(str string) (tokens []token) {
 	return tokens
 }
 
+// hasMark tells if the string has an unescaped `!`.
+func hasMark(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] == '\\' {
+			i++
+		} else if str[i] == '!' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isComparator tells if the string starts with a comparator followed by `!`.
+func isComparator(str string) bool {
+	return len(str) > 2 && str[2] == '!' && token{Value: str[:2]}.comparasionType() != ComparasionInvalid
+}
+
 type token struct {
-	Type  tokenType
-	Value string
+	Type   tokenType
+	Value  string
+	Offset int
 }
 
 // RemoveBackslash removes escape character `\`, except when its escaping itself.
@@ -322,18 +591,63 @@ func (t token) comparasionType() ComparasionType {
 		return ComparasionLessThan
 	case "lk":
 		return ComparasionLike
+	case "ne":
+		return ComparasionNotEquals
+	case "nl":
+		return ComparasionNotLike
+	case "is":
+		return ComparasionIsNull
+	case "bt":
+		return ComparasionBetween
+	case "bx":
+		return ComparasionBetweenExclusive
 	}
 
 	return ComparasionInvalid
 }
 
+// identifier returns how the comparator is written in QFL.
+func (c ComparasionType) identifier() string {
+	switch c {
+	case ComparasionEquals:
+		return "eq"
+	case ComparasionLessThan:
+		return "lt"
+	case ComparasionMoreThan:
+		return "gt"
+	case ComparasionLessOrEqual:
+		return "le"
+	case ComparasionMoreOrEqual:
+		return "ge"
+	case ComparasionLike:
+		return "lk"
+	case ComparasionNotEquals:
+		return "ne"
+	case ComparasionNotLike:
+		return "nl"
+	case ComparasionIsNull:
+		return "is!null"
+	case ComparasionIsNotNull:
+		return "is!notnull"
+	case ComparasionBetween:
+		return "bt"
+	case ComparasionBetweenExclusive:
+		return "bx"
+	}
+
+	return strconv.Itoa(int(c))
+}
+
 type tokenType uint
 
 const (
-	tokenMark = iota + 1
+	tokenMark tokenType = iota + 1
 	tokenBar
 	tokenComma
 	tokenIdentifier
 	tokenValue
 	tokenEnd
+	tokenOr
+	tokenOpen
+	tokenClose
 )
@@ -153,3 +153,214 @@ func TestParserIs(t *testing.T) {
 
 	}
 }
+
+func TestParserOrGroups(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+
+	fm, err := parser.Parse(map[string]string{"age": "lt!18~gt!65|lt!70"})
+	assert.NoError(t, err)
+
+	rules := fm.GetInt("age")
+	if assert.Equal(t, 3, len(rules)) {
+		assert.Equal(t, qfl.ComparasionLessThan, rules[0].Comparasion)
+		assert.Equal(t, qfl.ComparasionMoreThan, rules[1].Comparasion)
+		assert.Equal(t, qfl.ComparasionLessThan, rules[2].Comparasion)
+		assert.Equal(t, 70, rules[2].Values[0])
+	}
+
+	for age, expected := range map[int]bool{10: true, 30: false, 66: true, 80: false} {
+		ok, err := fm.Match(map[string]any{"age": age})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ok, "age %d", age)
+	}
+
+	fm, err = parser.Parse(map[string]string{"age": "(lt!18~gt!65)|(eq!10,30~ge!40)"})
+	assert.NoError(t, err)
+
+	for age, expected := range map[int]bool{10: true, 30: false, 66: true, 20: false} {
+		ok, err := fm.Match(map[string]any{"age": age})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ok, "age %d", age)
+	}
+}
+
+func TestParserInvalidGroups(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+
+	for _, value := range []string{"(lt!18~gt!65", "lt!18)", "()", "lt!18~", "(lt!18)(gt!1)", "lt!18|~gt!1"} {
+		_, err := parser.Parse(map[string]string{"age": value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParserEscapedSymbols(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddString("name")
+
+	fm, err := parser.Parse(map[string]string{"name": `eq!a\~b,\(c\)|lk!d\|e`})
+	assert.NoError(t, err)
+
+	rules := fm.GetString("name")
+	if assert.Equal(t, 2, len(rules)) {
+		assert.Equal(t, []string{"a~b", "(c)"}, rules[0].Values)
+		assert.Equal(t, []string{"d|e"}, rules[1].Values)
+	}
+
+	// Single values don't need to escape the group symbols
+	fm, err = parser.Parse(map[string]string{"name": "John (Jr.) ~ Doe"})
+	assert.NoError(t, err)
+	assert.Equal(t, "John (Jr.) ~ Doe", fm.GetString("name")[0].Values[0])
+
+	// Even at the start, as they have no comparator
+	for _, value := range []string{"(555) 123", "(foo)", "~foo", ")foo", "a~b)"} {
+		fm, err = parser.Parse(map[string]string{"name": value})
+		if assert.NoError(t, err, value) {
+			assert.Equal(t, []qfl.FilterRule[string]{
+				{Comparasion: qfl.ComparasionEquals, Values: []string{value}},
+			}, fm.GetString("name"), value)
+		}
+	}
+
+	// Closing parenthesis are kept in values when no group is open
+	fm, err = parser.Parse(map[string]string{"name": "eq!foo(bar)|lk!%(x)%"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []qfl.FilterRule[string]{
+			{Comparasion: qfl.ComparasionEquals, Values: []string{"foo(bar)"}},
+			{Comparasion: qfl.ComparasionLike, Values: []string{"%(x)%"}},
+		}, fm.GetString("name"))
+	}
+
+	fm, err = parser.Parse(map[string]string{"name": "(eq!a~lk!b)|eq!d)"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []qfl.FilterRule[string]{
+			{Comparasion: qfl.ComparasionEquals, Values: []string{"a"}},
+			{Comparasion: qfl.ComparasionLike, Values: []string{"b"}},
+			{Comparasion: qfl.ComparasionEquals, Values: []string{"d)"}},
+		}, fm.GetString("name"))
+	}
+}
+
+func TestParserNegation(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddString("status")
+	parser.AddString("name")
+	parser.AddString("city")
+
+	fm, err := parser.Parse(map[string]string{
+		"status": "ne!archived,deleted",
+		"name":   "nl!%test%",
+		"city":   "new york",
+	})
+	assert.NoError(t, err)
+
+	status := fm.GetString("status")
+	if assert.Equal(t, 1, len(status)) {
+		assert.Equal(t, qfl.ComparasionNotEquals, status[0].Comparasion)
+		assert.Equal(t, []string{"archived", "deleted"}, status[0].Values)
+	}
+
+	name := fm.GetString("name")
+	if assert.Equal(t, 1, len(name)) {
+		assert.Equal(t, qfl.ComparasionNotLike, name[0].Comparasion)
+		assert.Equal(t, "%test%", name[0].Values[0])
+	}
+
+	city := fm.GetString("city")
+	if assert.Equal(t, 1, len(city)) {
+		assert.Equal(t, qfl.ComparasionEquals, city[0].Comparasion)
+		assert.Equal(t, "new york", city[0].Values[0])
+	}
+
+	ok, err := fm.Match(map[string]any{"status": "active", "name": "John", "city": "new york"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = fm.Match(map[string]any{"status": "deleted", "name": "John", "city": "new york"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = fm.Match(map[string]any{"status": "active", "name": "a test", "city": "new york"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = parser.Parse(map[string]string{"name": "nl!a,b"})
+	assert.Error(t, err)
+}
+
+func TestParserPresence(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddTime("deleted_at")
+	parser.AddUint("manager_id")
+
+	fm, err := parser.Parse(map[string]string{
+		"deleted_at": "is!null",
+		"manager_id": "is!notnull|ne!42",
+	})
+	assert.NoError(t, err)
+
+	deleted := fm.GetTime("deleted_at")
+	if assert.Equal(t, 1, len(deleted)) {
+		assert.Equal(t, qfl.ComparasionIsNull, deleted[0].Comparasion)
+		assert.Empty(t, deleted[0].Values)
+	}
+
+	manager := fm.GetUint("manager_id")
+	if assert.Equal(t, 2, len(manager)) {
+		assert.Equal(t, qfl.ComparasionIsNotNull, manager[0].Comparasion)
+		assert.Equal(t, qfl.ComparasionNotEquals, manager[1].Comparasion)
+		assert.EqualValues(t, 42, manager[1].Values[0])
+	}
+
+	ok, err := fm.Match(map[string]any{"manager_id": 7})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = fm.Match(map[string]any{"deleted_at": time.Now(), "manager_id": 7})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = fm.Match(map[string]any{"manager_id": nil})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	for _, value := range []string{"is!nil", "is!null,notnull", "is!"} {
+		_, err := parser.Parse(map[string]string{"manager_id": value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParserRange(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddTime("createdAt")
+	parser.AddString("name")
+
+	fm, err := parser.Parse(map[string]string{
+		"age":       "bt!10,20",
+		"createdAt": "bx!2023-01-01T00:00:00Z,2024-01-01T00:00:00Z",
+		"name":      "bt!b,a",
+	})
+	assert.NoError(t, err)
+
+	age := fm.GetInt("age")
+	if assert.Equal(t, 1, len(age)) {
+		assert.Equal(t, qfl.ComparasionBetween, age[0].Comparasion)
+		assert.Equal(t, []int{10, 20}, age[0].Values)
+	}
+
+	createdAt := fm.GetTime("createdAt")
+	if assert.Equal(t, 1, len(createdAt)) {
+		assert.Equal(t, qfl.ComparasionBetweenExclusive, createdAt[0].Comparasion)
+		assert.Equal(t, 2, len(createdAt[0].Values))
+	}
+
+	for _, value := range []string{"bt!10", "bt!10,20,30", "bt!20,10", "bx!20,10"} {
+		_, err := parser.Parse(map[string]string{"age": value})
+		assert.Error(t, err, value)
+	}
+
+	_, err = parser.Parse(map[string]string{"createdAt": "bt!2024-01-01T00:00:00Z,2023-01-01T00:00:00Z"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package qfl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SetFields sets the keys of the fields to select, nil selects all of them.
+func (f *Filter) SetFields(keys ...string) {
+	f.fields = keys
+}
+
+// GetFields returns the keys of the fields to select, or nil if all of them
+// should be selected.
+func (f *Filter) GetFields() []string {
+	return f.fields
+}
+
+// AddField allows the keys to be used on the fields variable, they don't need
+// to be filter keys.
+func (p *Parser) AddField(keys ...string) {
+	p.fieldKeys = append(p.fieldKeys, keys...)
+}
+
+// parseFields parses the keys of the fields variable, which are separated by
+// commas. Repeated keys are only selected once.
+func (p Parser) parseFields(value string) ([]string, *ParseError) {
+	fields := []string{}
+	offset := 0
+	for key := range strings.SplitSeq(value, ",") {
+		tok := token{Offset: offset, Value: key}
+		offset += len(key) + 1
+
+		if key == "" {
+			return nil, newParseError(ParseErrorUnexpectedToken, tok, "key")
+		} else if !slices.Contains(p.fieldKeys, key) {
+			return nil, newParseError(ParseErrorUnknownKey, tok, p.fieldKeys...)
+		}
+
+		if !slices.Contains(fields, key) {
+			fields = append(fields, key)
+		}
+	}
+
+	return fields, nil
+}
+
+// projection returns the columns of the fields of the filter, or the default
+// columns when the filter doesn't select any field. Keys that aren't mapped to
+// a column are skipped, unless strict is set, and it's an error if none of
+// them is mapped so it never selects every column.
+func (w conditionWriter) projection(defaults []string, strict bool) ([]string, error) {
+	if w.filter.fields == nil {
+		return defaults, nil
+	}
+
+	columns := []string{}
+	for _, key := range w.filter.fields {
+		if _, ok := w.keys[key]; !ok && strict {
+			return nil, fmt.Errorf("key `%s` isn't mapped to a column", key)
+		} else if ok {
+			columns = append(columns, w.column(key))
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("none of the fields of the filter is mapped to a column")
+	}
+
+	return columns, nil
+}
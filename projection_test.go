@@ -0,0 +1,105 @@
+package qfl_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParser_AddField() {
+	parser := qfl.Parser{FieldsKey: "fields"}
+	parser.AddField("id", "name", "age")
+
+	filter, err := parser.Parse(map[string]string{"fields": "id,name"})
+	if err != nil {
+		// Treat error...
+	}
+
+	builder := qfl.SQLBuilder{
+		Filter: *filter,
+		Keys: map[string]string{
+			"id":   "u.id",
+			"name": "u.name",
+			"age":  "u.age",
+		},
+	}
+
+	err = builder.SelectFields("users u", "u.id", "u.name", "u.age")
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(builder.Builder.String())
+	// Output:
+	// SELECT u.id, u.name FROM users u
+}
+
+func TestParserFields(t *testing.T) {
+	parser := qfl.Parser{FieldsKey: "fields"}
+	parser.AddField("id", "name")
+
+	fm, err := parser.Parse(map[string]string{"fields": "name,id,name"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "id"}, fm.GetFields())
+
+	fm, err = parser.Parse(map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, fm.GetFields())
+
+	cases := []struct {
+		value  string
+		offset int
+		kind   qfl.ParseErrorKind
+	}{
+		{"id,password", 3, qfl.ParseErrorUnknownKey},
+		{"id,", 3, qfl.ParseErrorUnexpectedToken},
+	}
+
+	for _, c := range cases {
+		_, err := parser.Parse(map[string]string{"fields": c.value})
+
+		var pe *qfl.ParseError
+		if assert.True(t, errors.As(err, &pe), c.value) {
+			assert.Equal(t, "fields", pe.Key, c.value)
+			assert.Equal(t, c.offset, pe.Offset, c.value)
+			assert.Equal(t, c.kind, pe.Kind, c.value)
+		}
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.SetFields("name", "secret")
+
+	keys := map[string]string{"id": "id", "name": "name"}
+	query := qfl.SQLSelect{Filter: filter, Keys: keys, QuoteIdentifiers: true, Dialect: qfl.PostgresDialect{}}
+
+	sql, _, err := query.SelectFields("users", "id").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "name" FROM users`, sql)
+
+	query.Strict = true
+	_, _, err = query.Build()
+	assert.EqualError(t, err, "key `secret` isn't mapped to a column")
+
+	builder := qfl.SQLBuilder{Keys: keys}
+	assert.NoError(t, builder.SelectFields("users", "id", "name"))
+	assert.Equal(t, "SELECT id, name FROM users\n", builder.Builder.String())
+
+	builder = qfl.SQLBuilder{Filter: filter, Keys: keys, Strict: true}
+	assert.Error(t, builder.SelectFields("users"))
+
+	// Fields that aren't mapped never fall back to selecting every column
+	filter.SetFields("salary")
+	query = qfl.SQLSelect{Filter: filter, Keys: map[string]string{"id": "id"}}
+	_, _, err = query.SelectFields("users", "id", "name").Build()
+	assert.EqualError(t, err, "none of the fields of the filter is mapped to a column")
+
+	builder = qfl.SQLBuilder{Filter: filter, Keys: map[string]string{"id": "id"}}
+	assert.Error(t, builder.SelectFields("users", "id", "name"))
+	assert.Equal(t, "", builder.Builder.String())
+}
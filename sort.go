@@ -0,0 +1,142 @@
+package qfl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortNulls indicates where null values are placed when sorting.
+type SortNulls uint8
+
+const (
+	SortNullsDefault SortNulls = iota // database default
+	SortNullsFirst
+	SortNullsLast
+)
+
+// SortField is a key that the results should be sorted by.
+type SortField struct {
+	Key        string
+	Descending bool
+	Nulls      SortNulls
+}
+
+// AddSort appends fields to sort the results by, in order of precedence.
+func (f *Filter) AddSort(fields ...SortField) {
+	f.sort = append(slices.Clip(f.sort), fields...)
+}
+
+// GetSort returns the fields to sort the results by, in order of precedence.
+func (f *Filter) GetSort() []SortField {
+	return f.sort
+}
+
+// AddSort allows the keys to be used on the sort variable, they don't need to
+// be filter keys.
+func (p *Parser) AddSort(keys ...string) {
+	p.sortKeys = append(p.sortKeys, keys...)
+}
+
+// parseSort parses the fields of the sort variable, which are separated by
+// commas and follow the grammar below, where `-` sorts in descending order:
+//
+//	field = [ "+" | "-" ] key [ "!" ( "first" | "last" ) ]
+//
+// Query strings decode `+` as a space, so through `ParseURL` the `+` prefix
+// must be sent as `%2B`.
+func (p Parser) parseSort(value string) ([]SortField, *ParseError) {
+	fields := []SortField{}
+	offset := 0
+	for str := range strings.SplitSeq(value, ",") {
+		field := SortField{}
+		start := offset
+		offset += len(str) + 1
+
+		if rest, ok := strings.CutPrefix(str, "-"); ok {
+			field.Descending = true
+			str = rest
+			start++
+		} else if rest, ok := strings.CutPrefix(str, "+"); ok {
+			str = rest
+			start++
+		}
+
+		key, nulls, hasNulls := strings.Cut(str, "!")
+		if key == "" {
+			tok := token{Offset: start, Value: str}
+			return nil, newParseError(ParseErrorUnexpectedToken, tok, "key")
+		} else if !slices.Contains(p.sortKeys, key) {
+			tok := token{Offset: start, Value: key}
+			return nil, newParseError(ParseErrorUnknownKey, tok, p.sortKeys...)
+		}
+
+		field.Key = key
+		switch {
+		case !hasNulls:
+		case nulls == "first":
+			field.Nulls = SortNullsFirst
+		case nulls == "last":
+			field.Nulls = SortNullsLast
+		default:
+			tok := token{Offset: start + len(key) + 1, Value: nulls}
+			return nil, newParseError(ParseErrorUnexpectedToken, tok, "first", "last")
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// sortTerms returns the terms of the ORDER BY clause for the sort fields of
+// the filter, which are reversed when the cursor goes backward. Keys that
+// aren't mapped to a column are skipped, unless strict is set.
+func (w conditionWriter) sortTerms(strict bool) ([]string, error) {
+	nullsOrder := false
+	if d, ok := w.dialect.(NullsOrderDialect); ok {
+		nullsOrder = d.NullsOrder()
+	}
+
+	terms := []string{}
+	for _, field := range w.filter.sort {
+		if w.filter.cursor != nil && w.filter.cursor.Backward {
+			field.Descending = !field.Descending
+			switch field.Nulls {
+			case SortNullsFirst:
+				field.Nulls = SortNullsLast
+			case SortNullsLast:
+				field.Nulls = SortNullsFirst
+			}
+		}
+
+		if _, ok := w.keys[field.Key]; !ok && strict {
+			return nil, fmt.Errorf("key `%s` isn't mapped to a column", field.Key)
+		} else if !ok {
+			continue
+		}
+
+		column := w.column(field.Key)
+		direction := " ASC"
+		if field.Descending {
+			direction = " DESC"
+		}
+
+		// Databases without NULLS FIRST or LAST sort by a flag of the column
+		// being null before sorting by the column itself
+		switch {
+		case field.Nulls == SortNullsDefault:
+			terms = append(terms, column+direction)
+		case nullsOrder && field.Nulls == SortNullsFirst:
+			terms = append(terms, column+direction+" NULLS FIRST")
+		case nullsOrder:
+			terms = append(terms, column+direction+" NULLS LAST")
+		case field.Nulls == SortNullsFirst:
+			terms = append(terms, "CASE WHEN "+column+" IS NULL THEN 0 ELSE 1 END", column+direction)
+		default:
+			terms = append(terms, "CASE WHEN "+column+" IS NULL THEN 1 ELSE 0 END", column+direction)
+		}
+	}
+
+	return terms, nil
+}
@@ -0,0 +1,141 @@
+package qfl_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParser_AddSort() {
+	parser := qfl.Parser{SortKey: "sort"}
+	parser.AddInt("age")
+	parser.AddSort("created_at", "name")
+
+	filter, err := parser.Parse(map[string]string{
+		"age":  "gt!20",
+		"sort": "-created_at!last,name",
+	})
+	if err != nil {
+		// Treat error...
+	}
+
+	builder := qfl.SQLBuilder{
+		Filter:  *filter,
+		Keys:    map[string]string{"age": "age", "created_at": "created_at", "name": "name"},
+		Dialect: qfl.PostgresDialect{},
+	}
+
+	builder.Select("users", "id")
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+	builder.Sort()
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// SELECT id FROM users
+	// WHERE age > $1
+	// ORDER BY created_at DESC NULLS LAST, name ASC
+	//
+	// [20]
+}
+
+func TestParserSort(t *testing.T) {
+	parser := qfl.Parser{SortKey: "order"}
+	parser.AddSort("name", "age")
+
+	fm, err := parser.Parse(map[string]string{"order": "+name!first,-age"})
+	assert.NoError(t, err)
+	assert.Equal(t, []qfl.SortField{
+		{Key: "name", Nulls: qfl.SortNullsFirst},
+		{Key: "age", Descending: true},
+	}, fm.GetSort())
+
+	// Query strings decode `+` as a space, so it must be escaped
+	fm, err = parser.ParseURL(&url.URL{RawQuery: "order=%2Bname,-age"})
+	assert.NoError(t, err)
+	assert.Equal(t, []qfl.SortField{{Key: "name"}, {Key: "age", Descending: true}}, fm.GetSort())
+
+	_, err = parser.ParseURL(&url.URL{RawQuery: "order=+name"})
+	assert.Error(t, err)
+
+	cases := []struct {
+		value  string
+		offset int
+		token  string
+		kind   qfl.ParseErrorKind
+	}{
+		{"name,password", 5, "password", qfl.ParseErrorUnknownKey},
+		{"name,,age", 5, "", qfl.ParseErrorUnexpectedToken},
+		{"-", 1, "", qfl.ParseErrorUnexpectedToken},
+		{"name,-age!middle", 10, "middle", qfl.ParseErrorUnexpectedToken},
+	}
+
+	for _, c := range cases {
+		_, err := parser.Parse(map[string]string{"order": c.value})
+
+		var pe *qfl.ParseError
+		if assert.True(t, errors.As(err, &pe), c.value) {
+			assert.Equal(t, "order", pe.Key, c.value)
+			assert.Equal(t, c.offset, pe.Offset, c.value)
+			assert.Equal(t, c.token, pe.Token, c.value)
+			assert.Equal(t, c.kind, pe.Kind, c.value)
+		}
+	}
+}
+
+func TestSortNulls(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddSort(
+		qfl.SortField{Key: "name", Nulls: qfl.SortNullsFirst},
+		qfl.SortField{Key: "age", Descending: true, Nulls: qfl.SortNullsLast},
+		qfl.SortField{Key: "password"},
+	)
+
+	keys := map[string]string{"name": "name", "age": "age"}
+	cases := []struct {
+		dialect qfl.Dialect
+		order   string
+	}{
+		{qfl.SQLiteDialect{}, "ORDER BY name ASC NULLS FIRST, age DESC NULLS LAST\n"},
+		{qfl.MySQLDialect{}, "ORDER BY CASE WHEN name IS NULL THEN 0 ELSE 1 END, name ASC, " +
+			"CASE WHEN age IS NULL THEN 1 ELSE 0 END, age DESC\n"},
+	}
+
+	for _, c := range cases {
+		builder := qfl.SQLBuilder{Filter: filter, Keys: keys, Dialect: c.dialect}
+		assert.NoError(t, builder.Sort())
+		assert.Equal(t, c.order, builder.Builder.String())
+	}
+
+	builder := qfl.SQLBuilder{Filter: filter, Keys: keys, Strict: true}
+	assert.EqualError(t, builder.Sort(), "key `password` isn't mapped to a column")
+
+	query := qfl.SQLSelect{Filter: filter, Keys: keys, Dialect: qfl.OracleDialect{}}
+	query.Select("users").Order("ASC", "id")
+
+	sql, _, err := query.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users\nORDER BY name ASC NULLS FIRST, age DESC NULLS LAST, id ASC", sql)
+}
+
+func TestSortCopy(t *testing.T) {
+	f := qfl.Filter{}
+	f.AddSort(qfl.SortField{Key: "name"}, qfl.SortField{Key: "age"})
+	f.AddSort(qfl.SortField{Key: "id"})
+
+	g, h := f, f
+	g.AddSort(qfl.SortField{Key: "created"})
+	h.AddSort(qfl.SortField{Key: "updated"})
+
+	assert.Len(t, f.GetSort(), 3)
+	assert.Equal(t, "created", g.GetSort()[3].Key)
+	assert.Equal(t, "updated", h.GetSort()[3].Key)
+}
@@ -2,10 +2,13 @@ package qfl
 
 import (
 	"fmt"
-	"strconv"
+	"slices"
 	"strings"
 )
 
+// SQLPlaceholderFormat is the format of the placeholders of the parameters.
+//
+// Deprecated: Use `Dialect` instead, which supports more databases.
 type SQLPlaceholderFormat uint8
 
 const (
@@ -16,15 +19,38 @@ const (
 // SQLBuilder is a generic WHERE-condition builder capable to convert filter
 // rules automatically.
 //
+// The syntax of the query follows the `Dialect`, which defaults to the one
+// equivalent to `PlaceholderFormat` when it's nil.
+//
+// The builder keeps the parameters of every function, so fragments written by
+// `With`, `Join` and `Raw` can use `?` as placeholders, which are replaced by
+// the ones of the dialect numbered after the previous parameters.
+//
 // All the functions build the string on the builder in place, and it doesn't
-// check nor support if you call them in the wrong order. Use with caution
+// check nor support if you call them in the wrong order. Use with caution, or
+// use `SQLSelect` which writes the clauses in the right order.
 type SQLBuilder struct {
 	// string builder for the SQL query
 	Builder strings.Builder
 
 	Filter            Filter
 	Keys              map[string]string
-	PlaceholderFormat SQLPlaceholderFormat
+	PlaceholderFormat SQLPlaceholderFormat // only used when Dialect is nil
+	Dialect           Dialect
+	QuoteIdentifiers  bool // quote the columns of Keys using the dialect
+	Strict            bool // error on keys of the filter that aren't in Keys
+
+	params []any // parameters written so far
+}
+
+// dialect returns the dialect of the builder, falling back to the one
+// equivalent to the placeholder format.
+func (sq *SQLBuilder) dialect() Dialect {
+	if sq.Dialect != nil {
+		return sq.Dialect
+	}
+
+	return dialectFromFormat(sq.PlaceholderFormat)
 }
 
 func (sq *SQLBuilder) Select(table string, columns ...string) {
@@ -45,71 +71,325 @@ func (sq *SQLBuilder) Select(table string, columns ...string) {
 	sq.Builder.WriteRune('\n')
 }
 
+// SelectFields writes the SELECT clause with the columns of the fields of the
+// filter, or the default columns when it doesn't select any field. Keys that
+// aren't mapped to a column are skipped unless `Strict` is set, and it's an
+// error when none of them is mapped.
+func (sq *SQLBuilder) SelectFields(table string, defaults ...string) error {
+	w := conditionWriter{
+		filter:  &sq.Filter,
+		keys:    sq.Keys,
+		dialect: sq.dialect(),
+		quote:   sq.QuoteIdentifiers,
+	}
+
+	columns, err := w.projection(defaults, sq.Strict)
+	if err != nil {
+		return err
+	} else if len(columns) == 0 {
+		columns = []string{"*"}
+	}
+
+	sq.Select(table, columns...)
+	return nil
+}
+
+// Where writes the WHERE clause with the conditions of the filter, keys that
+// aren't mapped to a column are skipped unless `Strict` is set. Nothing is
+// written when there are no conditions.
 func (sq *SQLBuilder) Where() ([]any, error) {
+	return sq.WhereAnd("")
+}
+
+// WhereAnd writes the WHERE clause with the condition joined with the ones of
+// the filter by AND, using `?` as the placeholders of its arguments.
+func (sq *SQLBuilder) WhereAnd(condition string, args ...any) ([]any, error) {
 	if sq.Keys == nil {
 		return nil, fmt.Errorf("field `Keys` is empty")
 	}
 
-	parameters := []any{}
-	offset := uint(0)
-
-	sq.Builder.WriteString("WHERE ")
-	for i := range sq.Filter.keys {
-		key := sq.Filter.keys[i]
-		if column, ok := sq.Keys[key.key]; ok {
-			for i := range key.rules {
-				var (
-					params []any
-				)
-
-				switch key.Type {
-				case ruleTypeInt:
-					params = extractConditions(column, key.rules[i], sq.Filter.intVals, offset, sq.PlaceholderFormat, &sq.Builder)
-				case ruleTypeUint:
-					params = extractConditions(column, key.rules[i], sq.Filter.uintVals, offset, sq.PlaceholderFormat, &sq.Builder)
-				case ruleTypeFloat:
-					params = extractConditions(column, key.rules[i], sq.Filter.floatVals, offset, sq.PlaceholderFormat, &sq.Builder)
-				case ruleTypeString:
-					params = extractConditions(column, key.rules[i], sq.Filter.stringVals, offset, sq.PlaceholderFormat, &sq.Builder)
-				case ruleTypeTime:
-					params = extractConditions(column, key.rules[i], sq.Filter.timeVals, offset, sq.PlaceholderFormat, &sq.Builder)
-				}
-
-				parameters = append(parameters, params...)
-				offset += uint(len(params))
-
-				if i != len(key.rules)-1 {
-					sq.Builder.WriteString(" AND ")
-				}
+	if sq.params == nil {
+		sq.params = []any{}
+	}
+
+	var builder strings.Builder
+	w := conditionWriter{
+		filter:  &sq.Filter,
+		keys:    sq.Keys,
+		dialect: sq.dialect(),
+		quote:   sq.QuoteIdentifiers,
+		strict:  sq.Strict,
+		builder: &builder,
+	}
+
+	conditions := []sqlCondition{}
+	if condition != "" {
+		conditions = append(conditions, sqlCondition{condition, args})
+	}
+
+	params, ok, err := w.writeWhere(conditions, sq.params)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		sq.params = params
+		sq.Builder.WriteString(builder.String())
+		sq.Builder.WriteRune('\n')
+	}
+
+	return sq.Parameters(), nil
+}
+
+// Parameters returns the parameters of everything written so far, in the
+// order of their placeholders.
+func (sq *SQLBuilder) Parameters() []any {
+	return slices.Clone(sq.params)
+}
+
+// Raw writes a custom fragment of SQL, with `?` as the placeholders of its
+// arguments.
+func (sq *SQLBuilder) Raw(fragment string, args ...any) error {
+	return sq.writeFragment(fragment, args)
+}
+
+// writeFragment writes the fragment in a single line, or nothing if its
+// arguments doesn't match its placeholders.
+func (sq *SQLBuilder) writeFragment(fragment string, args []any) error {
+	var builder strings.Builder
+	params, err := writeFragment(fragment, args, sq.params, sq.dialect(), &builder)
+	if err != nil {
+		return err
+	}
+
+	sq.params = params
+	sq.Builder.WriteString(builder.String())
+	sq.Builder.WriteRune('\n')
+	return nil
+}
+
+// writeFragment writes a raw fragment of SQL, replacing its `?` markers with
+// the placeholders of the dialect and appending their arguments to the
+// parameters. Markers inside quotes are kept, and `??` writes a single `?`.
+//
+// Fragments without arguments are written unchanged, so they can use `?` as
+// an operator (like the ones of jsonb on Postgres) without escaping it.
+func writeFragment(fragment string, args []any, parameters []any, dialect Dialect, builder *strings.Builder) ([]any, error) {
+	if len(args) == 0 {
+		builder.WriteString(fragment)
+		return parameters, nil
+	}
+
+	var quote byte
+	n := 0
+	for i := 0; i < len(fragment); i++ {
+		c := fragment[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?' && i+1 < len(fragment) && fragment[i+1] == '?':
+			i++
+		case c == '?':
+			if n == len(args) {
+				return parameters, fmt.Errorf("fragment `%s` has more placeholders than arguments", fragment)
 			}
-		}
 
-		if i != len(sq.Filter.keys)-1 {
-			sq.Builder.WriteString(" AND ")
+			parameters = append(parameters, bindArg(dialect, len(parameters)+1, args[n]))
+			builder.WriteString(dialect.Placeholder(len(parameters)))
+			n++
+			continue
 		}
+
+		builder.WriteByte(c)
+	}
+
+	if n != len(args) {
+		return parameters, fmt.Errorf("fragment `%s` has %d placeholders, but %d arguments were given", fragment, n, len(args))
 	}
 
-	sq.Builder.WriteRune('\n')
 	return parameters, nil
 }
 
-func (sq *SQLBuilder) Join(table, condition string) {
-	sq.Builder.WriteString("JOIN ")
-	sq.Builder.WriteString(table)
-	sq.Builder.WriteString(" ON ")
-	sq.Builder.WriteString(condition)
-	sq.Builder.WriteRune('\n')
+// conditionWriter writes the rules of a filter as SQL conditions, using the
+// columns mapped to their keys.
+type conditionWriter struct {
+	filter  *Filter
+	keys    map[string]string
+	dialect Dialect
+	quote   bool // quote the columns using the dialect
+	strict  bool // error on keys of the filter that aren't mapped
+	builder *strings.Builder
+}
+
+// writeWhere writes the WHERE clause with the conditions, the one of the cursor
+// and the ones of the filter joined by AND, grouping them when there's more
+// than one. Keys that aren't mapped to a column are skipped unless strict is
+// set. It returns false if there are no conditions to write.
+func (w conditionWriter) writeWhere(conditions []sqlCondition, parameters []any) ([]any, bool, error) {
+	root, hasRoot, err := w.filter.mappedExpression(w.keys, w.strict, "column")
+	if err != nil {
+		return nil, false, err
+	}
+
+	cursor, ok, err := w.cursorCondition()
+	if err != nil {
+		return nil, false, err
+	} else if ok {
+		conditions = append(slices.Clip(conditions), cursor)
+	}
+
+	count := len(conditions)
+	if hasRoot {
+		count++
+	}
+
+	if count == 0 {
+		return parameters, false, nil
+	}
+
+	w.builder.WriteString("WHERE ")
+	for i := range conditions {
+		if i != 0 {
+			w.builder.WriteString(" AND ")
+		}
+
+		// The conditions may have OR, so they are grouped
+		if count > 1 {
+			w.builder.WriteRune('(')
+		}
+
+		parameters, err = writeFragment(conditions[i].condition, conditions[i].args, parameters, w.dialect, w.builder)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if count > 1 {
+			w.builder.WriteRune(')')
+		}
+	}
+
+	if hasRoot && len(conditions) != 0 {
+		w.builder.WriteString(" AND ")
+		parameters = w.writeNode(root, logicalAnd, parameters)
+	} else if hasRoot {
+		parameters = w.writeNode(root, root.op, parameters)
+	}
+
+	return parameters, true, nil
+}
+
+// column returns the column mapped to the key, quoted if enabled.
+func (w conditionWriter) column(key string) string {
+	if w.quote {
+		return w.dialect.QuoteIdentifier(w.keys[key])
+	}
+
+	return w.keys[key]
+}
+
+// writeNode writes the conditions of the node, using parenthesis when the
+// operator differs from its parent.
+func (w conditionWriter) writeNode(node ruleNode, parent logicalOp, parameters []any) []any {
+	if node.op == logicalLeaf {
+		key := w.filter.keys[node.key]
+		column := w.column(key.key)
+		offset := uint(len(parameters))
+
+		// Presence checks doesn't have values, so they don't use placeholders
+		switch key.rules[node.rule].Comparasion {
+		case ComparasionIsNull:
+			w.builder.WriteString(column)
+			w.builder.WriteString(" IS NULL")
+			return parameters
+		case ComparasionIsNotNull:
+			w.builder.WriteString(column)
+			w.builder.WriteString(" IS NOT NULL")
+			return parameters
+		}
+
+		var params []any
+		switch key.Type {
+		case ruleTypeInt:
+			params = extractConditions(column, key.rules[node.rule], w.filter.intVals, offset, w.dialect, w.builder)
+		case ruleTypeUint:
+			params = extractConditions(column, key.rules[node.rule], w.filter.uintVals, offset, w.dialect, w.builder)
+		case ruleTypeFloat:
+			params = extractConditions(column, key.rules[node.rule], w.filter.floatVals, offset, w.dialect, w.builder)
+		case ruleTypeString:
+			params = extractConditions(column, key.rules[node.rule], w.filter.stringVals, offset, w.dialect, w.builder)
+		case ruleTypeTime:
+			params = extractConditions(column, key.rules[node.rule], w.filter.timeVals, offset, w.dialect, w.builder)
+		}
+
+		return append(parameters, params...)
+	}
+
+	separator := " AND "
+	if node.op == logicalOr {
+		separator = " OR "
+	}
+
+	group := node.op != parent
+	if group {
+		w.builder.WriteRune('(')
+	}
+
+	for i := range node.children {
+		parameters = w.writeNode(node.children[i], node.op, parameters)
+
+		if i != len(node.children)-1 {
+			w.builder.WriteString(separator)
+		}
+	}
+
+	if group {
+		w.builder.WriteRune(')')
+	}
+
+	return parameters
+}
+
+func (sq *SQLBuilder) Join(table, condition string, args ...any) error {
+	return sq.writeFragment("JOIN "+table+" ON "+condition, args)
 }
 
 // Page paginates the query by the limit number. Pages are zero-indexed
 func (sq *SQLBuilder) Page(limit, page uint64) {
-	sq.Builder.WriteString("LIMIT ")
-	sq.Builder.Write(strconv.AppendUint(nil, limit, 10))
-	sq.Builder.WriteString(" OFFSET ")
-	sq.Builder.Write(strconv.AppendUint(nil, page*limit, 10))
+	sq.Builder.WriteString(sq.dialect().Paginate(limit, page*limit))
 	sq.Builder.WriteRune('\n')
 }
 
+// Sort writes the ORDER BY clause with the sort fields of the filter. Keys that
+// aren't mapped to a column are skipped unless `Strict` is set, and nothing is
+// written when there are no fields.
+func (sq *SQLBuilder) Sort() error {
+	w := conditionWriter{
+		filter:  &sq.Filter,
+		keys:    sq.Keys,
+		dialect: sq.dialect(),
+		quote:   sq.QuoteIdentifiers,
+	}
+
+	terms, err := w.sortTerms(sq.Strict)
+	if err != nil || len(terms) == 0 {
+		return err
+	}
+
+	sq.Builder.WriteString("ORDER BY ")
+	sq.Builder.WriteString(strings.Join(terms, ", "))
+	sq.Builder.WriteRune('\n')
+	return nil
+}
+
+// Paginate writes the page of the filter, or nothing if it has no page.
+func (sq *SQLBuilder) Paginate() {
+	if page := sq.Filter.page; page != nil {
+		sq.Page(page.Limit, page.Number)
+	}
+}
+
 func (sq *SQLBuilder) Order(order string, columns ...string) {
 	sq.Builder.WriteString("ORDER BY ")
 
@@ -127,34 +407,46 @@ func (sq *SQLBuilder) Order(order string, columns ...string) {
 	sq.Builder.WriteRune('\n')
 }
 
-func (sq *SQLBuilder) With(name string, statement string) {
-	sq.Builder.WriteString("WITH ")
-	sq.Builder.WriteString(name)
-	sq.Builder.WriteString(" AS ( ")
-	sq.Builder.WriteString(statement)
-	sq.Builder.WriteRune(')')
-	sq.Builder.WriteRune('\n')
+func (sq *SQLBuilder) With(name string, statement string, args ...any) error {
+	return sq.writeFragment("WITH "+name+" AS ( "+statement+")", args)
 }
 
-func extractConditions[T Primitive](column string, rule filterRule, values []T, offset uint, format SQLPlaceholderFormat, builder *strings.Builder) (params []any) {
+func extractConditions[T Primitive](column string, rule filterRule, values []T, offset uint, dialect Dialect, builder *strings.Builder) (params []any) {
 	params = make([]any, len(rule.indices))
 	for i := range rule.indices {
 		params[i] = values[rule.indices[i]]
 	}
 
+	// Lists without values can't be written as IN, but they are either
+	// always true or false
+	if len(params) == 0 {
+		builder.WriteString(dialect.Bool(rule.Comparasion == ComparasionNotEquals))
+		return
+	}
+
+	isList := rule.Comparasion == ComparasionEquals || rule.Comparasion == ComparasionNotEquals
+	if isList && len(params) > 1 {
+		list := make([]T, len(rule.indices))
+		for i := range rule.indices {
+			list[i] = values[rule.indices[i]]
+		}
+
+		params = writeList(column, rule.Comparasion == ComparasionNotEquals, params, list, offset, dialect, builder)
+		for i := range params {
+			params[i] = bindArg(dialect, int(offset)+i+1, params[i])
+		}
+
+		return
+	}
+
 	skipPlaceholder := false
 
 	builder.WriteString(column)
 	switch rule.Comparasion {
 	case ComparasionEquals:
-		if len(params) > 1 {
-			builder.WriteString(" IN ")
-			stringifyListParams(params, offset, format, builder)
-			skipPlaceholder = true
-		} else {
-			builder.WriteString(" = ")
-		}
-
+		builder.WriteString(" = ")
+	case ComparasionNotEquals:
+		builder.WriteString(" <> ")
 	case ComparasionLessThan:
 		builder.WriteString(" < ")
 	case ComparasionMoreThan:
@@ -163,33 +455,105 @@ func extractConditions[T Primitive](column string, rule filterRule, values []T,
 		builder.WriteString(" <= ")
 	case ComparasionMoreOrEqual:
 		builder.WriteString(" >= ")
-	case ComparasionLike:
-		builder.WriteString(" LIKE ")
+	case ComparasionLike, ComparasionNotLike:
+		if rule.Comparasion == ComparasionNotLike {
+			builder.WriteString(" NOT")
+		}
+		builder.WriteRune(' ')
+		builder.WriteString(dialect.Like())
+		builder.WriteRune(' ')
+
 		for i := range params {
-			params[i] = fmt.Sprint(params[rule.indices[i]])
+			params[i] = fmt.Sprint(params[i])
 		}
+	case ComparasionBetween:
+		builder.WriteString(" BETWEEN ")
+		builder.WriteString(dialect.Placeholder(int(offset) + 1))
+		builder.WriteString(" AND ")
+		builder.WriteString(dialect.Placeholder(int(offset) + 2))
+		skipPlaceholder = true
+	case ComparasionBetweenExclusive:
+		// There's no exclusive BETWEEN, so it's split into two conditions
+		builder.WriteString(" > ")
+		builder.WriteString(dialect.Placeholder(int(offset) + 1))
+		builder.WriteString(" AND ")
+		builder.WriteString(column)
+		builder.WriteString(" < ")
+		builder.WriteString(dialect.Placeholder(int(offset) + 2))
+		skipPlaceholder = true
 	}
 
-	if !skipPlaceholder && format == SQLPlaceholderDollarSign {
-		builder.WriteRune('$')
-		builder.Write(strconv.AppendInt(nil, int64(1+offset), 10))
-	} else if !skipPlaceholder {
-		builder.WriteRune('?')
+	if !skipPlaceholder {
+		builder.WriteString(dialect.Placeholder(int(offset) + 1))
+	}
+
+	for i := range params {
+		params[i] = bindArg(dialect, int(offset)+i+1, params[i])
 	}
 
 	return
 }
 
-func stringifyListParams(params []any, offset uint, format SQLPlaceholderFormat, builder *strings.Builder) {
-	builder.WriteRune('(')
+// writeList writes the condition for a list of values, either as a single
+// array parameter or as IN lists split by the limit of the dialect. It returns
+// the parameters used by the condition.
+func writeList(column string, not bool, params []any, list any, offset uint, dialect Dialect, builder *strings.Builder) []any {
+	if d, ok := dialect.(ArrayDialect); ok {
+		if array, ok := d.Array(list); ok {
+			builder.WriteString(column)
+			if not {
+				builder.WriteString(" <> ALL(")
+			} else {
+				builder.WriteString(" = ANY(")
+			}
+			builder.WriteString(dialect.Placeholder(int(offset) + 1))
+			builder.WriteRune(')')
 
-	for i := range params {
-		if format == SQLPlaceholderDollarSign {
-			builder.WriteRune('$')
-			builder.Write(strconv.AppendInt(nil, int64(1+offset)+int64(i), 10))
+			return []any{array}
+		}
+	}
+
+	size := len(params)
+	if d, ok := dialect.(ChunkDialect); ok && d.ListLimit() > 0 {
+		size = d.ListLimit()
+	}
+
+	// Values are in the list if they are in any of the chunks, or not in the
+	// list if they aren't in all of them
+	chunked := len(params) > size
+	if chunked {
+		builder.WriteRune('(')
+	}
+
+	for start := 0; start < len(params); start += size {
+		end := min(start+size, len(params))
+		if start != 0 && not {
+			builder.WriteString(" AND ")
+		} else if start != 0 {
+			builder.WriteString(" OR ")
+		}
+
+		builder.WriteString(column)
+		if not {
+			builder.WriteString(" NOT IN ")
 		} else {
-			builder.WriteRune('?')
+			builder.WriteString(" IN ")
 		}
+		stringifyListParams(params[start:end], offset+uint(start), dialect, builder)
+	}
+
+	if chunked {
+		builder.WriteRune(')')
+	}
+
+	return params
+}
+
+func stringifyListParams(params []any, offset uint, dialect Dialect, builder *strings.Builder) {
+	builder.WriteRune('(')
+
+	for i := range params {
+		builder.WriteString(dialect.Placeholder(int(offset) + i + 1))
 
 		if i != len(params)-1 {
 			builder.WriteRune(',')
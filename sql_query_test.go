@@ -2,8 +2,11 @@ package qfl_test
 
 import (
 	"fmt"
+	"testing"
 
 	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func ExampleSQLBuilder() {
@@ -44,3 +47,224 @@ func ExampleSQLBuilder() {
 	//
 	// [Roberto% 23 60 3000 Programmer Developer]
 }
+
+func ExampleSQLBuilder_orGroups() {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+	parser.AddString("role")
+
+	filter, err := parser.Parse(map[string]string{
+		"age":  "lt!18~gt!65|lt!70",
+		"role": "eq!Programmer~lk!%Tester",
+	})
+	if err != nil {
+		// Treat error...
+	}
+
+	builder := qfl.SQLBuilder{
+		Filter: *filter,
+		Keys: map[string]string{
+			"age":  "age",
+			"role": "role",
+		},
+		PlaceholderFormat: qfl.SQLPlaceholderDollarSign,
+	}
+
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// WHERE (age < $1 OR (age > $2 AND age < $3)) AND (role = $4 OR role LIKE $5)
+	//
+	// [18 65 70 Programmer %Tester]
+}
+
+func ExampleSQLBuilder_negation() {
+	filter := qfl.Filter{}
+	filter.AddString("status", []string{"archived", "deleted"}, qfl.ComparasionNotEquals)
+	filter.AddUint("owner", []uint{42}, qfl.ComparasionNotEquals)
+	filter.AddString("name", []string{"%test%"}, qfl.ComparasionNotLike)
+
+	builder := qfl.SQLBuilder{
+		Filter: filter,
+		Keys: map[string]string{
+			"status": "status",
+			"owner":  "owner_id",
+			"name":   "name",
+		},
+	}
+
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// WHERE status NOT IN (?,?) AND owner_id <> ? AND name NOT LIKE ?
+	//
+	// [archived deleted 42 %test%]
+}
+
+func ExampleSQLBuilder_presence() {
+	filter := qfl.Filter{}
+	filter.AddNull("deleted_at", qfl.ComparasionIsNull)
+	filter.AddUint("manager", []uint{42}, qfl.ComparasionNotEquals)
+	filter.AddNull("manager", qfl.ComparasionIsNotNull)
+
+	builder := qfl.SQLBuilder{
+		Filter: filter,
+		Keys: map[string]string{
+			"deleted_at": "deleted_at",
+			"manager":    "manager_id",
+		},
+		PlaceholderFormat: qfl.SQLPlaceholderDollarSign,
+	}
+
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// WHERE deleted_at IS NULL AND manager_id <> $1 AND manager_id IS NOT NULL
+	//
+	// [42]
+}
+
+func ExampleSQLBuilder_range() {
+	filter := qfl.Filter{}
+	filter.AddUint("age", []uint{18}, qfl.ComparasionEquals)
+	filter.AddFloat("salary", []float64{1000, 3000}, qfl.ComparasionBetween)
+	filter.AddUint("hours", []uint{20, 40}, qfl.ComparasionBetweenExclusive)
+
+	builder := qfl.SQLBuilder{
+		Filter: filter,
+		Keys: map[string]string{
+			"age":    "age",
+			"salary": "salary",
+			"hours":  "week_hours",
+		},
+		PlaceholderFormat: qfl.SQLPlaceholderDollarSign,
+	}
+
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// WHERE age = $1 AND salary BETWEEN $2 AND $3 AND week_hours > $4 AND week_hours < $5
+	//
+	// [18 1000 3000 20 40]
+}
+
+func ExampleSQLBuilder_parameters() {
+	filter := qfl.Filter{}
+	filter.AddUint("age", []uint{18}, qfl.ComparasionMoreOrEqual)
+
+	builder := qfl.SQLBuilder{
+		Filter:  filter,
+		Keys:    map[string]string{"age": "u.age"},
+		Dialect: qfl.PostgresDialect{},
+	}
+
+	builder.With("recent", "SELECT * FROM orders WHERE total > ?", 100)
+	builder.Select("recent o", "o.id")
+	builder.Join("users u", "u.id = o.user_id AND u.country = ?", "BR")
+	builder.Where()
+	builder.Raw("AND o.status <> ?", "canceled")
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(builder.Parameters())
+	// Output:
+	// WITH recent AS ( SELECT * FROM orders WHERE total > $1)
+	// SELECT o.id FROM recent o
+	// JOIN users u ON u.id = o.user_id AND u.country = $2
+	// WHERE u.age >= $3
+	// AND o.status <> $4
+	//
+	// [100 BR 18 canceled]
+}
+
+func TestSQLBuilderFragments(t *testing.T) {
+	builder := qfl.SQLBuilder{Keys: map[string]string{}, Dialect: qfl.SQLServerDialect{}}
+
+	assert.NoError(t, builder.Raw(`SELECT '?', "a?" FROM t WHERE data ?? 'k' AND id = ?`, 1))
+	assert.Error(t, builder.Raw("AND a = ? AND b = ?", 1))
+	assert.Error(t, builder.Join("users u", "u.id = t.user_id", 1))
+
+	params, err := builder.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT '?', \"a?\" FROM t WHERE data ? 'k' AND id = @p1\n", builder.Builder.String())
+	assert.Equal(t, []any{1}, params)
+}
+
+func TestSQLBuilderFragmentsWithoutArgs(t *testing.T) {
+	builder := qfl.SQLBuilder{Keys: map[string]string{}, Dialect: qfl.PostgresDialect{}}
+
+	// Fragments without arguments can use `?` as an operator
+	assert.NoError(t, builder.With("x", "SELECT * FROM t WHERE data ? 'k'"))
+	builder.Select("x", "id")
+	assert.NoError(t, builder.Join("users u", "u.tags ?| array['a']"))
+	assert.NoError(t, builder.Raw("WHERE x.data ?& array['b']"))
+
+	assert.Equal(t, "WITH x AS ( SELECT * FROM t WHERE data ? 'k')\n"+
+		"SELECT id FROM x\n"+
+		"JOIN users u ON u.tags ?| array['a']\n"+
+		"WHERE x.data ?& array['b']\n", builder.Builder.String())
+	assert.Empty(t, builder.Parameters())
+}
+
+func TestSQLBuilderEmptyWhere(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddString("password", []string{"secret"}, qfl.ComparasionEquals)
+
+	builder := qfl.SQLBuilder{Filter: filter, Keys: map[string]string{"name": "name"}}
+	params, err := builder.Where()
+	assert.NoError(t, err)
+	assert.Empty(t, params)
+	assert.Equal(t, "", builder.Builder.String())
+
+	builder = qfl.SQLBuilder{Filter: filter, Keys: map[string]string{"name": "name"}, Strict: true}
+	_, err = builder.Where()
+	assert.EqualError(t, err, "key `password` isn't mapped to a column")
+	assert.Equal(t, "", builder.Builder.String())
+}
+
+func TestSQLBuilderWhereAnd(t *testing.T) {
+	parser := qfl.Parser{}
+	parser.AddInt("age")
+
+	filter, err := parser.Parse(map[string]string{"age": "lt!20~gt!60"})
+	assert.NoError(t, err)
+
+	builder := qfl.SQLBuilder{
+		Filter:  *filter,
+		Keys:    map[string]string{"age": "age"},
+		Dialect: qfl.PostgresDialect{},
+	}
+
+	params, err := builder.WhereAnd("tenant_id = ? OR public", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE (tenant_id = $1 OR public) AND (age < $2 OR age > $3)\n", builder.Builder.String())
+	assert.Equal(t, []any{7, 20, 60}, params)
+
+	builder = qfl.SQLBuilder{Keys: map[string]string{}, Dialect: qfl.PostgresDialect{}}
+	params, err = builder.WhereAnd("tenant_id = ?", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE tenant_id = $1\n", builder.Builder.String())
+	assert.Equal(t, []any{7}, params)
+
+	_, err = builder.WhereAnd("tenant_id = ? AND org_id = ?", 7)
+	assert.Error(t, err)
+}
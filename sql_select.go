@@ -0,0 +1,235 @@
+package qfl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SQLSelect is a SELECT query builder that collects its clauses and writes
+// them in the right order on `Build`, no matter the order they were added.
+//
+// The WHERE clause is built from the rules of `Filter` using the columns of
+// `Keys` and the conditions given to `Where`, and it's omitted when there are
+// no conditions. Keys that aren't mapped to a column are skipped unless
+// `Strict` is set.
+//
+// Fragments given to `With` and joins can use `?` as the placeholders of
+// their arguments, which are numbered along with the ones of the filter.
+type SQLSelect struct {
+	Filter           Filter
+	Keys             map[string]string
+	Dialect          Dialect // defaults to `MySQLDialect`
+	QuoteIdentifiers bool    // quote the columns of Keys using the dialect
+	Strict           bool    // error on keys of the filter that aren't in Keys
+
+	ctes    []sqlWith
+	table   string
+	columns []string
+	project bool // select the fields of the filter instead of the columns
+	joins   []sqlJoin
+	where   []sqlCondition
+	groupBy []string
+	orderBy []string
+	limit   uint64
+	offset  uint64
+	paged   bool
+}
+
+type sqlWith struct {
+	name      string
+	statement string
+	args      []any
+}
+
+type sqlJoin struct {
+	kind      string
+	table     string
+	condition string
+	args      []any
+}
+
+type sqlCondition struct {
+	condition string
+	args      []any
+}
+
+// With adds a common table expression to the query.
+func (sq *SQLSelect) With(name, statement string, args ...any) *SQLSelect {
+	sq.ctes = append(sq.ctes, sqlWith{name, statement, args})
+	return sq
+}
+
+// Select sets the table and the columns of the query, replacing the previous
+// ones.
+func (sq *SQLSelect) Select(table string, columns ...string) *SQLSelect {
+	sq.table = table
+	sq.columns = columns
+	sq.project = false
+	return sq
+}
+
+// SelectFields sets the table of the query and selects the columns of the
+// fields of the filter, or the default columns when it doesn't select any
+// field. Keys that aren't mapped to a column are skipped unless `Strict` is
+// set, and `Build` fails when none of them is mapped.
+func (sq *SQLSelect) SelectFields(table string, defaults ...string) *SQLSelect {
+	sq.Select(table, defaults...)
+	sq.project = true
+	return sq
+}
+
+// Join adds an inner join to the query.
+func (sq *SQLSelect) Join(table, condition string, args ...any) *SQLSelect {
+	return sq.join("JOIN", table, condition, args)
+}
+
+// LeftJoin adds a left join to the query.
+func (sq *SQLSelect) LeftJoin(table, condition string, args ...any) *SQLSelect {
+	return sq.join("LEFT JOIN", table, condition, args)
+}
+
+func (sq *SQLSelect) join(kind, table, condition string, args []any) *SQLSelect {
+	sq.joins = append(sq.joins, sqlJoin{kind, table, condition, args})
+	return sq
+}
+
+// Where adds a condition to the WHERE clause, joined with the other ones and
+// the ones of the filter by AND.
+func (sq *SQLSelect) Where(condition string, args ...any) *SQLSelect {
+	sq.where = append(sq.where, sqlCondition{condition, args})
+	return sq
+}
+
+// GroupBy adds columns to the GROUP BY clause.
+func (sq *SQLSelect) GroupBy(columns ...string) *SQLSelect {
+	sq.groupBy = append(sq.groupBy, columns...)
+	return sq
+}
+
+// Order adds columns to the ORDER BY clause, all of them sorted by the same
+// order. They come after the sort fields of the filter.
+func (sq *SQLSelect) Order(order string, columns ...string) *SQLSelect {
+	for i := range columns {
+		sq.orderBy = append(sq.orderBy, columns[i]+" "+order)
+	}
+
+	return sq
+}
+
+// Page paginates the query by the limit number, replacing the previous
+// pagination and the page of the filter. Pages are zero-indexed
+func (sq *SQLSelect) Page(limit, page uint64) *SQLSelect {
+	sq.limit = limit
+	sq.offset = page * limit
+	sq.paged = true
+	return sq
+}
+
+// Build writes the query and returns it along with its parameters.
+func (sq *SQLSelect) Build() (string, []any, error) {
+	if sq.table == "" {
+		return "", nil, fmt.Errorf("query has no table, call `Select` first")
+	} else if sq.Keys == nil && len(sq.Filter.keys) != 0 {
+		return "", nil, fmt.Errorf("field `Keys` is empty")
+	}
+
+	dialect := sq.Dialect
+	if dialect == nil {
+		dialect = MySQLDialect{}
+	}
+
+	var builder, where strings.Builder
+	var err error
+	parameters := []any{}
+	w := conditionWriter{
+		filter:  &sq.Filter,
+		keys:    sq.Keys,
+		dialect: dialect,
+		quote:   sq.QuoteIdentifiers,
+		strict:  sq.Strict,
+		builder: &where,
+	}
+
+	columns := sq.columns
+	if sq.project {
+		columns, err = w.projection(columns, sq.Strict)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	for i := range sq.ctes {
+		if i == 0 {
+			builder.WriteString("WITH ")
+		} else {
+			builder.WriteString(", ")
+		}
+
+		builder.WriteString(sq.ctes[i].name)
+		builder.WriteString(" AS (")
+		parameters, err = writeFragment(sq.ctes[i].statement, sq.ctes[i].args, parameters, dialect, &builder)
+		if err != nil {
+			return "", nil, err
+		}
+		builder.WriteRune(')')
+	}
+
+	if len(sq.ctes) != 0 {
+		builder.WriteRune('\n')
+	}
+
+	builder.WriteString("SELECT ")
+	if len(columns) == 0 {
+		builder.WriteRune('*')
+	} else {
+		builder.WriteString(strings.Join(columns, ", "))
+	}
+	builder.WriteString(" FROM ")
+	builder.WriteString(sq.table)
+
+	for _, join := range sq.joins {
+		builder.WriteRune('\n')
+		builder.WriteString(join.kind)
+		builder.WriteRune(' ')
+		builder.WriteString(join.table)
+		builder.WriteString(" ON ")
+		parameters, err = writeFragment(join.condition, join.args, parameters, dialect, &builder)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	parameters, ok, err := w.writeWhere(sq.where, parameters)
+	if err != nil {
+		return "", nil, err
+	} else if ok {
+		builder.WriteRune('\n')
+		builder.WriteString(where.String())
+	}
+
+	if len(sq.groupBy) != 0 {
+		builder.WriteString("\nGROUP BY ")
+		builder.WriteString(strings.Join(sq.groupBy, ", "))
+	}
+
+	terms, err := w.sortTerms(sq.Strict)
+	if err != nil {
+		return "", nil, err
+	}
+
+	terms = append(terms, sq.orderBy...)
+	if len(terms) != 0 {
+		builder.WriteString("\nORDER BY ")
+		builder.WriteString(strings.Join(terms, ", "))
+	}
+
+	if sq.paged {
+		builder.WriteRune('\n')
+		builder.WriteString(dialect.Paginate(sq.limit, sq.offset))
+	} else if page := sq.Filter.page; page != nil {
+		builder.WriteRune('\n')
+		builder.WriteString(dialect.Paginate(page.Limit, page.Offset()))
+	}
+
+	return builder.String(), parameters, nil
+}
@@ -0,0 +1,154 @@
+package qfl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleSQLSelect() {
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{20}, qfl.ComparasionMoreThan)
+	filter.AddString("name", []string{"Rob%"}, qfl.ComparasionLike)
+
+	query := qfl.SQLSelect{
+		Filter: filter,
+		Keys: map[string]string{
+			"age":  "u.age",
+			"name": "u.name",
+		},
+		Dialect: qfl.PostgresDialect{},
+	}
+
+	// Clauses can be added in any order
+	query.Page(20, 1).
+		Order("DESC", "u.created_at").
+		Join("teams t", "t.id = u.team_id").
+		Select("users u", "u.id", "u.name", "t.name")
+
+	sql, params, err := query.Build()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(sql)
+	fmt.Println(params)
+	// Output:
+	// SELECT u.id, u.name, t.name FROM users u
+	// JOIN teams t ON t.id = u.team_id
+	// WHERE u.age > $1 AND u.name LIKE $2
+	// ORDER BY u.created_at DESC
+	// LIMIT 20 OFFSET 20
+	// [20 Rob%]
+}
+
+func TestSQLSelectClauses(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddUint("team", []uint{1, 2}, qfl.ComparasionEquals)
+
+	query := qfl.SQLSelect{
+		Filter:           filter,
+		Keys:             map[string]string{"team": "t.id"},
+		QuoteIdentifiers: true,
+	}
+
+	query.GroupBy("t.id").
+		Order("ASC", "t.id", "t.name").
+		LeftJoin("users u", "u.team_id = t.id").
+		With("active", "SELECT * FROM teams WHERE active").
+		With("recent", "SELECT * FROM active WHERE created_at > NOW()").
+		Select("teams t", "t.id", "COUNT(u.id)")
+
+	sql, params, err := query.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH active AS (SELECT * FROM teams WHERE active), recent AS (SELECT * FROM active WHERE created_at > NOW())\n"+
+		"SELECT t.id, COUNT(u.id) FROM teams t\n"+
+		"LEFT JOIN users u ON u.team_id = t.id\n"+
+		"WHERE `t`.`id` IN (?,?)\n"+
+		"GROUP BY t.id\n"+
+		"ORDER BY t.id ASC, t.name ASC", sql)
+	assert.Equal(t, []any{uint(1), uint(2)}, params)
+}
+
+func TestSQLSelectWithoutConditions(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("secret", []int{1}, qfl.ComparasionEquals)
+
+	query := qfl.SQLSelect{Filter: filter, Keys: map[string]string{}}
+	query.Select("users")
+
+	sql, params, err := query.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users", sql)
+	assert.Empty(t, params)
+}
+
+func TestSQLSelectInvalid(t *testing.T) {
+	query := qfl.SQLSelect{}
+	_, _, err := query.Build()
+	assert.Error(t, err)
+
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{1}, qfl.ComparasionEquals)
+
+	query = qfl.SQLSelect{Filter: filter}
+	query.Select("users")
+	_, _, err = query.Build()
+	assert.Error(t, err)
+}
+
+func TestSQLSelectParameters(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddString("status", []string{"open", "closed"}, qfl.ComparasionEquals)
+
+	query := qfl.SQLSelect{
+		Filter:  filter,
+		Keys:    map[string]string{"status": "o.status"},
+		Dialect: qfl.OracleDialect{},
+	}
+
+	query.Join("users u", "u.id = o.user_id AND u.country = ?", "BR").
+		With("recent", "SELECT * FROM orders WHERE total > ?", 100).
+		Select("recent o", "o.id")
+
+	sql, params, err := query.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH recent AS (SELECT * FROM orders WHERE total > :1)\n"+
+		"SELECT o.id FROM recent o\n"+
+		"JOIN users u ON u.id = o.user_id AND u.country = :2\n"+
+		"WHERE o.status IN (:3,:4)", sql)
+	assert.Equal(t, []any{100, "BR", "open", "closed"}, params)
+
+	query.LeftJoin("teams t", "t.id = u.team_id AND t.name = ?", "a", "b")
+	_, _, err = query.Build()
+	assert.Error(t, err)
+}
+
+func TestSQLSelectWhere(t *testing.T) {
+	filter := qfl.Filter{}
+	filter.AddInt("age", []int{20}, qfl.ComparasionMoreThan)
+	filter.AddString("password", []string{"secret"}, qfl.ComparasionEquals)
+
+	query := qfl.SQLSelect{
+		Filter:  filter,
+		Keys:    map[string]string{"age": "age"},
+		Dialect: qfl.PostgresDialect{},
+	}
+
+	query.Select("users").
+		Where("tenant_id = ? OR public", 7).
+		Where("deleted_at IS NULL")
+
+	sql, params, err := query.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users\n"+
+		"WHERE (tenant_id = $1 OR public) AND (deleted_at IS NULL) AND age > $2", sql)
+	assert.Equal(t, []any{7, 20}, params)
+
+	query.Strict = true
+	_, _, err = query.Build()
+	assert.EqualError(t, err, "key `password` isn't mapped to a column")
+}
@@ -0,0 +1,141 @@
+package qfl
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewParserFromStruct builds a parser with the fields of the struct that have
+// the `qfl` tag, inferring the type of the key from the type of the field. It
+// also returns the mapping of keys to columns, to be used on `SQLBuilder`.
+//
+// The tag starts with the name of the key, defaulting to the field name, and
+// is followed by the options below separated by commas:
+//
+//	column=name  column of the key, defaults to the key name
+//	ops=eq|gt    comparators that are allowed on the key
+//	values=n     maximum amount of values of a rule
+//	rules=n      maximum amount of rules of the key
+//	length=n     maximum length of the values
+//
+// For example: `qfl:"age,column=u.age,ops=eq|gt|lt"`. Fields tagged with `-`
+// are ignored.
+func NewParserFromStruct[T any]() (*Parser, map[string]string, error) {
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("type `%s` is not a struct", t)
+	}
+
+	p := &Parser{}
+	columns := map[string]string{}
+	for _, field := range reflect.VisibleFields(t) {
+		tag, ok := field.Tag.Lookup("qfl")
+		if !ok || tag == "-" || !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		key, options, _ := strings.Cut(tag, ",")
+		if key == "" {
+			key = field.Name
+		}
+
+		column := key
+		opts := []KeyOption{}
+		for option := range strings.SplitSeq(options, ",") {
+			if option == "" {
+				continue
+			}
+
+			name, value, _ := strings.Cut(option, "=")
+			switch name {
+			case "column":
+				column = value
+			case "ops":
+				comparators, err := parseComparators(value)
+				if err != nil {
+					return nil, nil, fmt.Errorf("field `%s`: %w", field.Name, err)
+				}
+				opts = append(opts, AllowComparators(comparators...))
+			case "values", "rules", "length":
+				n, err := strconv.Atoi(value)
+				if err != nil || n <= 0 {
+					return nil, nil, fmt.Errorf("field `%s`: option `%s` must be a positive number", field.Name, name)
+				}
+
+				switch name {
+				case "values":
+					opts = append(opts, MaxValues(n))
+				case "rules":
+					opts = append(opts, MaxRules(n))
+				case "length":
+					opts = append(opts, MaxLength(n))
+				}
+			default:
+				return nil, nil, fmt.Errorf("field `%s`: unknown option `%s`", field.Name, name)
+			}
+		}
+
+		ruleType, ok := fieldRuleType(field.Type)
+		if !ok {
+			return nil, nil, fmt.Errorf("field `%s` has unsupported type `%s`", field.Name, field.Type)
+		}
+
+		p.keys = append(p.keys, key)
+		p.types = append(p.types, ruleType)
+		p.options = append(p.options, newKeyOptions(opts))
+		columns[key] = column
+	}
+
+	return p, columns, nil
+}
+
+// parseComparators parses a list of comparators separated by `|`, where `is`
+// allows both presence checks.
+func parseComparators(str string) ([]ComparasionType, error) {
+	comparators := []ComparasionType{}
+	for op := range strings.SplitSeq(str, "|") {
+		comparasion := token{Value: op}.comparasionType()
+		switch comparasion {
+		case ComparasionInvalid:
+			return nil, fmt.Errorf("comparator `%s` is invalid", op)
+		case ComparasionIsNull:
+			comparators = append(comparators, ComparasionIsNull, ComparasionIsNotNull)
+		default:
+			comparators = append(comparators, comparasion)
+		}
+	}
+
+	return comparators, nil
+}
+
+// fieldRuleType returns the rule type for the type of a field, pointers are
+// treated as their element so nullable columns can be used.
+func fieldRuleType(t reflect.Type) (ruleType, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeFor[time.Time]() {
+		return ruleTypeTime, true
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ruleTypeInt, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ruleTypeUint, true
+	case reflect.Float32, reflect.Float64:
+		return ruleTypeFloat, true
+	case reflect.String:
+		return ruleTypeString, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,109 @@
+package qfl_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID        uint       `qfl:"id,ops=eq"`
+	Name      string     `qfl:"name,column=u.name,ops=eq|lk,length=50"`
+	Age       int8       `qfl:"age,column=u.age,ops=eq|gt|lt,rules=2"`
+	Score     float32    `qfl:",values=1"`
+	DeletedAt *time.Time `qfl:"deleted_at,ops=is"`
+	Password  string     `qfl:"-"`
+	Email     string
+}
+
+func ExampleNewParserFromStruct() {
+	parser, columns, err := qfl.NewParserFromStruct[user]()
+	if err != nil {
+		// Treat error...
+	}
+
+	filter, err := parser.Parse(map[string]string{
+		"name":       "lk!Rob%",
+		"age":        "gt!20",
+		"deleted_at": "is!null",
+	})
+	if err != nil {
+		// Treat error...
+	}
+
+	builder := qfl.SQLBuilder{
+		Filter:            *filter,
+		Keys:              columns,
+		PlaceholderFormat: qfl.SQLPlaceholderDollarSign,
+	}
+
+	params, err := builder.Where()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(builder.Builder.String())
+	fmt.Println(params)
+	// Output:
+	// WHERE u.name LIKE $1 AND u.age > $2 AND deleted_at IS NULL
+	//
+	// [Rob% 20]
+}
+
+func TestNewParserFromStruct(t *testing.T) {
+	parser, columns, err := qfl.NewParserFromStruct[*user]()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"id":         "id",
+		"name":       "u.name",
+		"age":        "u.age",
+		"Score":      "Score",
+		"deleted_at": "deleted_at",
+	}, columns)
+
+	fm, err := parser.Parse(map[string]string{
+		"id":         "42",
+		"Score":      "9.5",
+		"deleted_at": "is!notnull",
+		"Password":   "secret",
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []uint{42}, fm.GetUint("id")[0].Values)
+	assert.Equal(t, []float64{9.5}, fm.GetFloat("Score")[0].Values)
+	assert.Equal(t, qfl.ComparasionIsNotNull, fm.GetTime("deleted_at")[0].Comparasion)
+	assert.Nil(t, fm.GetString("Password"))
+
+	for key, value := range map[string]string{
+		"id":    "gt!1",
+		"age":   "gt!1|lt!5|eq!3",
+		"Score": "eq!1,2",
+		"name":  "nl!x",
+	} {
+		_, err := parser.Parse(map[string]string{key: value})
+		assert.Error(t, err, key)
+	}
+}
+
+func TestNewParserFromStructInvalid(t *testing.T) {
+	_, _, err := qfl.NewParserFromStruct[struct {
+		Tags []string `qfl:"tags"`
+	}]()
+	assert.Error(t, err)
+
+	_, _, err = qfl.NewParserFromStruct[struct {
+		Name string `qfl:"name,ops=eq|xx"`
+	}]()
+	assert.Error(t, err)
+
+	_, _, err = qfl.NewParserFromStruct[struct {
+		Name string `qfl:"name,size=10"`
+	}]()
+	assert.Error(t, err)
+
+	_, _, err = qfl.NewParserFromStruct[int]()
+	assert.Error(t, err)
+}
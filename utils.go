@@ -1,5 +1,7 @@
 package qfl
 
+import "fmt"
+
 // Generates a sequence of numbers from start to end with one step
 func generateSequence(start, end int) []int {
 	indices := make([]int, end-start)
@@ -9,3 +11,35 @@ func generateSequence(start, end int) []int {
 
 	return indices
 }
+
+// mappedField returns the field mapped to the key, where nil keys map every
+// key to itself.
+func mappedField(keys map[string]string, key string) (string, bool) {
+	if keys == nil {
+		return key, true
+	}
+
+	field, ok := keys[key]
+	return field, ok
+}
+
+// mappedExpression returns the expression of the filter without the keys that
+// aren't mapped to a field, or an error for them if strict is set, where
+// target names what the keys are mapped to. It returns false if there are no
+// conditions left.
+func (f *Filter) mappedExpression(keys map[string]string, strict bool, target string) (ruleNode, bool, error) {
+	if strict {
+		for _, key := range f.keys {
+			if _, ok := mappedField(keys, key.key); !ok && len(key.rules) != 0 {
+				return ruleNode{}, false, fmt.Errorf("key `%s` isn't mapped to a %s", key.key, target)
+			}
+		}
+	}
+
+	root, ok := f.expression().prune(func(leaf ruleNode) bool {
+		_, ok := mappedField(keys, f.keys[leaf.key].key)
+		return ok
+	})
+
+	return root, ok, nil
+}
@@ -0,0 +1,211 @@
+package qfl
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// FilterBuilder builds a filter with typed methods, so callers of QFL APIs
+// don't write the syntax by hand. Rules are added to the key of the last call
+// to one of the `Where` functions, which sets the type of its values, and all
+// of them are combined by AND.
+//
+// Invalid rules are reported when the filter is built, and each build returns
+// a new filter.
+type FilterBuilder struct {
+	filter Filter
+	err    error // first invalid rule
+}
+
+// KeyBuilder adds the rules of a key with values of type T. It has the
+// methods of its `FilterBuilder`, so it can continue with other keys.
+type KeyBuilder[T Primitive] struct {
+	*FilterBuilder
+	key string
+	add func(string, []T, ComparasionType)
+}
+
+// WhereInt starts a builder with the rules of the int key.
+func WhereInt(key string) *KeyBuilder[int] {
+	return (&FilterBuilder{}).WhereInt(key)
+}
+
+// WhereUint starts a builder with the rules of the uint key.
+func WhereUint(key string) *KeyBuilder[uint] {
+	return (&FilterBuilder{}).WhereUint(key)
+}
+
+// WhereFloat starts a builder with the rules of the float key.
+func WhereFloat(key string) *KeyBuilder[float64] {
+	return (&FilterBuilder{}).WhereFloat(key)
+}
+
+// WhereString starts a builder with the rules of the string key.
+func WhereString(key string) *KeyBuilder[string] {
+	return (&FilterBuilder{}).WhereString(key)
+}
+
+// WhereTime starts a builder with the rules of the time key.
+func WhereTime(key string) *KeyBuilder[time.Time] {
+	return (&FilterBuilder{}).WhereTime(key)
+}
+
+// WhereInt changes the key of the next rules to the int key.
+func (fb *FilterBuilder) WhereInt(key string) *KeyBuilder[int] {
+	return whereKey(fb, key, ruleTypeInt, fb.filter.AddInt)
+}
+
+// WhereUint changes the key of the next rules to the uint key.
+func (fb *FilterBuilder) WhereUint(key string) *KeyBuilder[uint] {
+	return whereKey(fb, key, ruleTypeUint, fb.filter.AddUint)
+}
+
+// WhereFloat changes the key of the next rules to the float key.
+func (fb *FilterBuilder) WhereFloat(key string) *KeyBuilder[float64] {
+	return whereKey(fb, key, ruleTypeFloat, fb.filter.AddFloat)
+}
+
+// WhereString changes the key of the next rules to the string key.
+func (fb *FilterBuilder) WhereString(key string) *KeyBuilder[string] {
+	return whereKey(fb, key, ruleTypeString, fb.filter.AddString)
+}
+
+// WhereTime changes the key of the next rules to the time key.
+func (fb *FilterBuilder) WhereTime(key string) *KeyBuilder[time.Time] {
+	return whereKey(fb, key, ruleTypeTime, fb.filter.AddTime)
+}
+
+// whereKey returns the builder of the key, which is invalid if the key already
+// has values of another type.
+func whereKey[T Primitive](fb *FilterBuilder, key string, ruleType ruleType, add func(string, []T, ComparasionType)) *KeyBuilder[T] {
+	for _, k := range fb.filter.keys {
+		if k.key == key && !k.hasType(ruleType) && fb.err == nil {
+			fb.err = fmt.Errorf("key `%s` has values of a different type", key)
+		}
+	}
+
+	return &KeyBuilder[T]{FilterBuilder: fb, key: key, add: add}
+}
+
+func (kb *KeyBuilder[T]) Eq(value T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionEquals, value)
+}
+
+func (kb *KeyBuilder[T]) Ne(value T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionNotEquals, value)
+}
+
+func (kb *KeyBuilder[T]) Lt(value T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionLessThan, value)
+}
+
+func (kb *KeyBuilder[T]) Gt(value T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionMoreThan, value)
+}
+
+func (kb *KeyBuilder[T]) Le(value T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionLessOrEqual, value)
+}
+
+func (kb *KeyBuilder[T]) Ge(value T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionMoreOrEqual, value)
+}
+
+// In adds a rule that matches any of the values.
+func (kb *KeyBuilder[T]) In(values ...T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionEquals, values...)
+}
+
+// NotIn adds a rule that matches none of the values.
+func (kb *KeyBuilder[T]) NotIn(values ...T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionNotEquals, values...)
+}
+
+// Like adds a rule that matches the LIKE pattern.
+func (kb *KeyBuilder[T]) Like(pattern T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionLike, pattern)
+}
+
+// NotLike adds a rule that doesn't match the LIKE pattern.
+func (kb *KeyBuilder[T]) NotLike(pattern T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionNotLike, pattern)
+}
+
+// Between adds a rule that matches values between the bounds, including them.
+func (kb *KeyBuilder[T]) Between(lower, upper T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionBetween, lower, upper)
+}
+
+// BetweenExclusive adds a rule that matches values between the bounds,
+// excluding them.
+func (kb *KeyBuilder[T]) BetweenExclusive(lower, upper T) *KeyBuilder[T] {
+	return kb.addRule(ComparasionBetweenExclusive, lower, upper)
+}
+
+func (kb *KeyBuilder[T]) IsNull() *KeyBuilder[T] {
+	kb.filter.AddNull(kb.key, ComparasionIsNull)
+	return kb
+}
+
+func (kb *KeyBuilder[T]) IsNotNull() *KeyBuilder[T] {
+	kb.filter.AddNull(kb.key, ComparasionIsNotNull)
+	return kb
+}
+
+// Filter returns the built filter, or the error of the first invalid rule.
+func (fb *FilterBuilder) Filter() (*Filter, error) {
+	if fb.err != nil {
+		return nil, fb.err
+	}
+
+	// Copies of the filter don't share the rules added later
+	filter := fb.filter
+	return &filter, nil
+}
+
+// Values returns the query variables of the filter, encoded the same way as
+// `Parser.Encode` with times in RFC3339 with nanoseconds.
+func (fb *FilterBuilder) Values() (url.Values, error) {
+	filter, err := fb.Filter()
+	if err != nil {
+		return nil, err
+	}
+
+	// The parser accepts the keys of the filter with their own types, keys
+	// with only null rules can be of any type
+	parser := Parser{TimeFormat: time.RFC3339Nano}
+	for _, key := range filter.keys {
+		ruleType := key.Type
+		if ruleType == ruleTypeNone {
+			ruleType = ruleTypeString
+		}
+
+		parser.keys = append(parser.keys, key.key)
+		parser.types = append(parser.types, ruleType)
+		parser.options = append(parser.options, newKeyOptions(nil))
+	}
+
+	return parser.Encode(filter)
+}
+
+// Query returns the query string of the filter, with the variables sorted by
+// key.
+func (fb *FilterBuilder) Query() (string, error) {
+	vals, err := fb.Values()
+	if err != nil {
+		return "", err
+	}
+
+	return vals.Encode(), nil
+}
+
+// addRule adds the rule to the key, where rules without values are invalid.
+func (kb *KeyBuilder[T]) addRule(comparasion ComparasionType, values ...T) *KeyBuilder[T] {
+	if len(values) == 0 && kb.err == nil {
+		kb.err = fmt.Errorf("rule of key `%s` has no values", kb.key)
+	}
+
+	kb.add(kb.key, values, comparasion)
+	return kb
+}
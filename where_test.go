@@ -0,0 +1,74 @@
+package qfl_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robertoesteves13/qfl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleWhereInt() {
+	query, err := qfl.WhereInt("age").Gt(20).Lt(60).WhereString("role").In("admin", "staff").Query()
+	if err != nil {
+		// Treat error...
+	}
+
+	fmt.Println(query)
+	// Output:
+	// age=gt%2120%7Clt%2160&role=eq%21admin%2Cstaff
+}
+
+func TestFilterBuilder(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	builder := qfl.WhereInt("age").Between(18, 60).Ne(30).
+		WhereUint("id").NotIn(1, 2).
+		WhereFloat("score").Ge(0.5).
+		WhereString("name").Like("jo%").IsNotNull().
+		WhereTime("created").Le(created).
+		WhereString("deleted").IsNull()
+
+	filter, err := builder.Filter()
+	assert.NoError(t, err)
+	assert.Equal(t, []qfl.FilterRule[int]{
+		{Comparasion: qfl.ComparasionBetween, Values: []int{18, 60}},
+		{Comparasion: qfl.ComparasionNotEquals, Values: []int{30}},
+	}, filter.GetInt("age"))
+	assert.Equal(t, []qfl.FilterRule[uint]{
+		{Comparasion: qfl.ComparasionNotEquals, Values: []uint{1, 2}},
+	}, filter.GetUint("id"))
+	assert.Equal(t, []qfl.FilterRule[time.Time]{
+		{Comparasion: qfl.ComparasionLessOrEqual, Values: []time.Time{created}},
+	}, filter.GetTime("created"))
+
+	vals, err := builder.Values()
+	assert.NoError(t, err)
+	assert.Equal(t, "bt!18,60|ne!30", vals.Get("age"))
+	assert.Equal(t, "ne!1,2", vals.Get("id"))
+	assert.Equal(t, "ge!0.5", vals.Get("score"))
+	assert.Equal(t, "lk!jo%|is!notnull", vals.Get("name"))
+	assert.Equal(t, "le!2024-01-02T03:04:05.000000006Z", vals.Get("created"))
+	assert.Equal(t, "is!null", vals.Get("deleted"))
+
+	// Building again returns a different filter
+	other, err := builder.WhereInt("age").Eq(40).Filter()
+	assert.NoError(t, err)
+	assert.Len(t, other.GetInt("age"), 3)
+	assert.Len(t, filter.GetInt("age"), 2)
+}
+
+func TestFilterBuilderErrors(t *testing.T) {
+	builders := []*qfl.FilterBuilder{
+		qfl.WhereInt("age").Gt(20).WhereString("age").Lt("60").FilterBuilder,
+		qfl.WhereString("name").IsNull().WhereInt("name").Eq(1).WhereUint("name").Eq(2).FilterBuilder,
+		qfl.WhereInt("age").In().FilterBuilder,
+		qfl.WhereString("name").Eq("").FilterBuilder,
+	}
+
+	for i, builder := range builders {
+		_, err := builder.Query()
+		assert.Error(t, err, i)
+	}
+}